@@ -0,0 +1,190 @@
+package changelog
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jayvynl/goctl-openapi/diff"
+	"github.com/jayvynl/goctl-openapi/internal/docutil"
+)
+
+type (
+	// Changelog is changes between two documents grouped by tag.
+	Changelog struct {
+		Title string
+		Tags  []*TagChanges
+	}
+
+	TagChanges struct {
+		Tag        string
+		Added      []diff.Change
+		Removed    []diff.Change
+		Deprecated []diff.Change
+		// Changed are other changes grouped by operation, in the order of operations.
+		Changed []*OperationChanges
+	}
+
+	OperationChanges struct {
+		Method      string
+		Path        string
+		OperationID string
+		Changes     []diff.Change
+	}
+)
+
+// New compares documents and groups changes by the first tag of operations.
+func New(title string, base, revision *openapi3.T) *Changelog {
+	return FromChanges(title, diff.Compare(base, revision))
+}
+
+// FromChanges groups changes by the first tag of operations.
+func FromChanges(title string, changes []diff.Change) *Changelog {
+	cl := &Changelog{Title: title}
+	tags := make(map[string]*TagChanges)
+	for _, c := range changes {
+		tag := docutil.GroupTag(c.Tags)
+		tc, ok := tags[tag]
+		if !ok {
+			tc = &TagChanges{Tag: tag}
+			tags[tag] = tc
+			cl.Tags = append(cl.Tags, tc)
+		}
+
+		switch c.Kind {
+		case diff.KindOperationAdded:
+			tc.Added = append(tc.Added, c)
+		case diff.KindOperationRemoved:
+			tc.Removed = append(tc.Removed, c)
+		case diff.KindOperationDeprecated, diff.KindPropertyDeprecated:
+			tc.Deprecated = append(tc.Deprecated, c)
+		default:
+			tc.addChanged(c)
+		}
+	}
+
+	sort.Slice(cl.Tags, func(i, j int) bool {
+		return cl.Tags[i].Tag < cl.Tags[j].Tag
+	})
+	for _, tc := range cl.Tags {
+		sortByOperation(tc.Added)
+		sortByOperation(tc.Removed)
+		sortByOperation(tc.Deprecated)
+		sort.SliceStable(tc.Changed, func(i, j int) bool {
+			a, b := tc.Changed[i], tc.Changed[j]
+			if a.Path != b.Path {
+				return a.Path < b.Path
+			}
+			return a.Method < b.Method
+		})
+	}
+	return cl
+}
+
+func (tc *TagChanges) addChanged(c diff.Change) {
+	for _, oc := range tc.Changed {
+		if oc.Method == c.Method && oc.Path == c.Path {
+			oc.Changes = append(oc.Changes, c)
+			return
+		}
+	}
+	tc.Changed = append(tc.Changed, &OperationChanges{
+		Method:      c.Method,
+		Path:        c.Path,
+		OperationID: c.OperationID,
+		Changes:     []diff.Change{c},
+	})
+}
+
+func sortByOperation(changes []diff.Change) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Path != changes[j].Path {
+			return changes[i].Path < changes[j].Path
+		}
+		return changes[i].Method < changes[j].Method
+	})
+}
+
+// Markdown writes changelog in markdown format.
+func (cl *Changelog) Markdown(w io.Writer) error {
+	var sb strings.Builder
+	if cl.Title != "" {
+		fmt.Fprintf(&sb, "# %s\n\n", cl.Title)
+	}
+	if len(cl.Tags) == 0 {
+		sb.WriteString("No changes.\n")
+	}
+
+	for _, tc := range cl.Tags {
+		fmt.Fprintf(&sb, "## %s\n\n", tc.Tag)
+		if len(tc.Added) > 0 {
+			sb.WriteString("### Added\n\n")
+			for _, c := range tc.Added {
+				fmt.Fprintf(&sb, "- %s\n", operationTitle(c.Method, c.Path, c.Summary))
+			}
+			sb.WriteString("\n")
+		}
+		if len(tc.Removed) > 0 {
+			sb.WriteString("### Removed\n\n")
+			for _, c := range tc.Removed {
+				fmt.Fprintf(&sb, "- %s%s\n", severityPrefix(c.Severity), operationTitle(c.Method, c.Path, c.Summary))
+			}
+			sb.WriteString("\n")
+		}
+		if len(tc.Deprecated) > 0 {
+			sb.WriteString("### Deprecated\n\n")
+			for _, c := range tc.Deprecated {
+				if c.Location == "" {
+					fmt.Fprintf(&sb, "- %s\n", operationTitle(c.Method, c.Path, c.Summary))
+				} else {
+					fmt.Fprintf(&sb, "- %s `%s`\n", operationTitle(c.Method, c.Path, ""), c.Location)
+				}
+			}
+			sb.WriteString("\n")
+		}
+		if len(tc.Changed) > 0 {
+			sb.WriteString("### Changed\n\n")
+			for _, oc := range tc.Changed {
+				fmt.Fprintf(&sb, "#### %s\n\n", operationTitle(oc.Method, oc.Path, ""))
+				for _, c := range oc.Changes {
+					sb.WriteString("- ")
+					sb.WriteString(severityPrefix(c.Severity))
+					if c.Location != "" {
+						fmt.Fprintf(&sb, "`%s`: ", c.Location)
+					}
+					sb.WriteString(escape(c.Message))
+					sb.WriteString("\n")
+				}
+				sb.WriteString("\n")
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func operationTitle(method, path, summary string) string {
+	if summary == "" {
+		return fmt.Sprintf("`%s %s`", method, path)
+	}
+	return fmt.Sprintf("`%s %s` %s", method, path, escape(summary))
+}
+
+func severityPrefix(s diff.Severity) string {
+	switch s {
+	case diff.SeverityBreaking:
+		return "**Breaking:** "
+	case diff.SeverityWarning:
+		return "**Warning:** "
+	}
+	return ""
+}
+
+// escape escapes markdown special characters in plain text.
+func escape(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "<", "&lt;", ">", "&gt;")
+	return replacer.Replace(s)
+}
@@ -0,0 +1,112 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jayvynl/goctl-openapi/diff"
+)
+
+func TestFromChanges(t *testing.T) {
+	changes := []diff.Change{
+		{Severity: diff.SeverityInfo, Kind: diff.KindOperationAdded, Method: "POST", Path: "/books", Tags: []string{"book"}},
+		{Severity: diff.SeverityBreaking, Kind: diff.KindOperationRemoved, Method: "DELETE", Path: "/books/{id}", Tags: []string{"book"}},
+		{Severity: diff.SeverityWarning, Kind: diff.KindOperationDeprecated, Method: "GET", Path: "/authors", Tags: []string{"author"}},
+		{Severity: diff.SeverityInfo, Kind: diff.KindPropertyDeprecated, Method: "GET", Path: "/authors", Tags: []string{"author"}, Location: "query.name"},
+		{Severity: diff.SeverityBreaking, Kind: diff.KindPropertyRequired, Method: "PUT", Path: "/books/{id}", Tags: []string{"book"}, Location: "body.name"},
+		{Severity: diff.SeverityInfo, Kind: diff.KindPropertyAdded, Method: "GET", Path: "/books", Tags: []string{"book"}, Location: "response.200.body.isbn"},
+		{Severity: diff.SeverityInfo, Kind: diff.KindPropertyAdded, Method: "PUT", Path: "/books/{id}", Tags: []string{"book"}, Location: "body.isbn"},
+		{Severity: diff.SeverityInfo, Kind: diff.KindOperationAdded, Method: "GET", Path: "/health"},
+	}
+	cl := FromChanges("Changelog", changes)
+
+	var tags []string
+	for _, tc := range cl.Tags {
+		tags = append(tags, tc.Tag)
+	}
+	if got := strings.Join(tags, ","); got != "author,book,default" {
+		t.Fatalf("tags = %s, want author,book,default", got)
+	}
+
+	author, book, def := cl.Tags[0], cl.Tags[1], cl.Tags[2]
+	tests := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{"author deprecated", len(author.Deprecated), 2},
+		{"author changed", len(author.Changed), 0},
+		{"book added", len(book.Added), 1},
+		{"book removed", len(book.Removed), 1},
+		{"book changed operations", len(book.Changed), 2},
+		{"default added", len(def.Added), 1},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %d, want %d", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// changed operations are sorted by path, changes of an operation are grouped.
+	if oc := book.Changed[0]; oc.Path != "/books" || len(oc.Changes) != 1 {
+		t.Errorf("first changed operation = %s %s with %d changes", oc.Method, oc.Path, len(oc.Changes))
+	}
+	if oc := book.Changed[1]; oc.Path != "/books/{id}" || len(oc.Changes) != 2 {
+		t.Errorf("second changed operation = %s %s with %d changes", oc.Method, oc.Path, len(oc.Changes))
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		name    string
+		title   string
+		changes []diff.Change
+		want    string
+	}{
+		{
+			name:  "no changes",
+			title: "Changelog",
+			want:  "# Changelog\n\nNo changes.\n",
+		},
+		{
+			name: "without title",
+			changes: []diff.Change{
+				{Severity: diff.SeverityInfo, Kind: diff.KindOperationAdded, Method: "GET", Path: "/health", Summary: "health_check"},
+			},
+			want: "## default\n\n### Added\n\n- `GET /health` health\\_check\n\n",
+		},
+		{
+			name:  "all sections",
+			title: "v2",
+			changes: []diff.Change{
+				{Severity: diff.SeverityInfo, Kind: diff.KindOperationAdded, Method: "POST", Path: "/books", Tags: []string{"book"}, Summary: "Create book"},
+				{Severity: diff.SeverityBreaking, Kind: diff.KindOperationRemoved, Method: "DELETE", Path: "/books/{id}", Tags: []string{"book"}},
+				{Severity: diff.SeverityInfo, Kind: diff.KindPropertyDeprecated, Method: "GET", Path: "/books", Tags: []string{"book"}, Location: "query.name"},
+				{Severity: diff.SeverityBreaking, Kind: diff.KindEnumNarrowed, Method: "PUT", Path: "/books/{id}", Tags: []string{"book"}, Location: "body.kind", Message: `enum values removed: ["*"]`},
+				{Severity: diff.SeverityWarning, Kind: diff.KindDefaultChanged, Method: "PUT", Path: "/books/{id}", Tags: []string{"book"}, Location: "body.size", Message: "default changed from 1 to 2"},
+				{Severity: diff.SeverityInfo, Kind: diff.KindPropertyAdded, Method: "PUT", Path: "/books/{id}", Tags: []string{"book"}, Location: "body.isbn", Message: "property added"},
+			},
+			want: "# v2\n\n" +
+				"## book\n\n" +
+				"### Added\n\n- `POST /books` Create book\n\n" +
+				"### Removed\n\n- **Breaking:** `DELETE /books/{id}`\n\n" +
+				"### Deprecated\n\n- `GET /books` `query.name`\n\n" +
+				"### Changed\n\n" +
+				"#### `PUT /books/{id}`\n\n" +
+				"- **Breaking:** `body.kind`: enum values removed: [\"\\*\"]\n" +
+				"- **Warning:** `body.size`: default changed from 1 to 2\n" +
+				"- `body.isbn`: property added\n\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := FromChanges(tt.title, tt.changes).Markdown(&sb); err != nil {
+				t.Fatal(err)
+			}
+			if sb.String() != tt.want {
+				t.Errorf("Markdown() =\n%s\nwant\n%s", sb.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jayvynl/goctl-openapi/changelog"
+)
+
+func runChangelog(args []string) {
+	fs := flag.NewFlagSet("changelog", flag.ExitOnError)
+	title := fs.String("title", "Changelog", `title of the changelog, empty string omits the title.`)
+	output := fs.String("o", "-", `output file, "-" will output to stdout.`)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: goctl-openapi changelog [flags] base revision\n")
+		fmt.Fprintf(fs.Output(), "base and revision are api files, or openapi files in json or yaml format.\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	base, err := loadDoc(fs.Arg(0))
+	if err != nil {
+		fmt.Printf("goctl-openapi: %s\n", err)
+		os.Exit(1)
+	}
+	revision, err := loadDoc(fs.Arg(1))
+	if err != nil {
+		fmt.Printf("goctl-openapi: %s\n", err)
+		os.Exit(1)
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Printf("goctl-openapi: %s\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err = changelog.New(*title, base, revision).Markdown(w); err != nil {
+		fmt.Printf("goctl-openapi: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jayvynl/goctl-openapi/diff"
+)
+
+func runDiff(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	format := fs.String("format", "text", `output format, "text" or "json".`)
+	severity := fs.String("severity", "info", `minimum severity of reported changes, "breaking", "warning" or "info".`)
+	failOn := fs.String("fail-on", "breaking", `exit with status 1 if there is any change of this severity or higher, "breaking", "warning", "info" or "none".`)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: goctl-openapi diff [flags] base revision\n")
+		fmt.Fprintf(fs.Output(), "base and revision are api files, or openapi files in json or yaml format.\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if *format != "text" && *format != "json" {
+		fmt.Printf("goctl-openapi: invalid format \"%s\", expect text or json\n", *format)
+		os.Exit(2)
+	}
+	minSeverity, err := diff.ParseSeverity(*severity)
+	if err != nil {
+		fmt.Printf("goctl-openapi: %s\n", err)
+		os.Exit(2)
+	}
+	var failSeverity diff.Severity
+	if *failOn != "none" {
+		if failSeverity, err = diff.ParseSeverity(*failOn); err != nil {
+			fmt.Printf("goctl-openapi: %s\n", err)
+			os.Exit(2)
+		}
+	}
+
+	base, err := loadDoc(fs.Arg(0))
+	if err != nil {
+		fmt.Printf("goctl-openapi: %s\n", err)
+		os.Exit(2)
+	}
+	revision, err := loadDoc(fs.Arg(1))
+	if err != nil {
+		fmt.Printf("goctl-openapi: %s\n", err)
+		os.Exit(2)
+	}
+
+	all := diff.Compare(base, revision)
+	var changes []diff.Change
+	for _, c := range all {
+		if c.Severity.Rank() >= minSeverity.Rank() {
+			changes = append(changes, c)
+		}
+	}
+	count := diff.Count(changes)
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if changes == nil {
+			changes = []diff.Change{}
+		}
+		_ = encoder.Encode(struct {
+			Changes []diff.Change         `json:"changes"`
+			Summary map[diff.Severity]int `json:"summary"`
+		}{
+			Changes: changes,
+			Summary: count,
+		})
+	case "text":
+		for _, c := range changes {
+			fmt.Println(c)
+		}
+		fmt.Printf("%d breaking, %d warning, %d info\n",
+			count[diff.SeverityBreaking], count[diff.SeverityWarning], count[diff.SeverityInfo])
+	}
+
+	if failSeverity != "" {
+		for _, c := range all {
+			if c.Severity.Rank() >= failSeverity.Rank() {
+				os.Exit(1)
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jayvynl/goctl-openapi/importer"
+)
+
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	service := fs.String("service", "", `service name, default derived from info title.`)
+	output := fs.String("o", "-", `output api file, "-" will output to stdout.`)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: goctl-openapi import [flags] file\n")
+		fmt.Fprintf(fs.Output(), "file is an openapi file in json or yaml format.\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	doc, err := loadDoc(fs.Arg(0))
+	if err != nil {
+		fmt.Printf("goctl-openapi: %s\n", err)
+		os.Exit(1)
+	}
+	data, warnings, err := importer.Import(doc, importer.Options{Service: *service})
+	// warnings go to stderr, api file may be written to stdout.
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "goctl-openapi: %s\n", w)
+	}
+	if err != nil {
+		fmt.Printf("goctl-openapi: %s\n", err)
+		os.Exit(1)
+	}
+
+	if *output == "-" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = os.WriteFile(*output, data, 0644)
+	}
+	if err != nil {
+		fmt.Printf("goctl-openapi: %s\n", err)
+		os.Exit(1)
+	}
+}
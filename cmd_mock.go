@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/jayvynl/goctl-openapi/mock"
+)
+
+func runMock(args []string) {
+	fs := flag.NewFlagSet("mock", flag.ExitOnError)
+	addr := fs.String("addr", ":8888", `listen address.`)
+	latency := fs.Duration("latency", 0, `delay of every response, e.g. "200ms".`)
+	errorRate := fs.Float64("error-rate", 0, `probability of responding with an injected error, from 0 to 1.`)
+	errorStatus := fs.Int("error-status", http.StatusInternalServerError, `status of injected errors.`)
+	noValidate := fs.Bool("no-validate", false, `don't validate requests against parameters and request bodies.`)
+	cors := fs.Bool("cors", true, `allow requests from any origin.`)
+	exampleDepth := fs.Int("example-depth", 0, `how many times a recursive schema is expanded in synthesized responses, default 1.`)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: goctl-openapi mock [flags] file\n")
+		fmt.Fprintf(fs.Output(), "file is an api file, or an openapi file in json or yaml format.\n")
+		fmt.Fprintf(fs.Output(), "Responses are examples of the document, or synthesized from schemas, header %s chooses a documented response status.\n", mock.StatusHeader)
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	doc, err := loadDoc(fs.Arg(0))
+	if err != nil {
+		fmt.Printf("goctl-openapi: %s\n", err)
+		os.Exit(1)
+	}
+	handler, err := mock.New(doc, mock.Options{
+		Latency:        *latency,
+		ErrorRate:      *errorRate,
+		ErrorStatus:    *errorStatus,
+		SkipValidation: *noValidate,
+		CORS:           *cors,
+		MaxDepth:       *exampleDepth,
+	})
+	if err != nil {
+		fmt.Printf("goctl-openapi: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("goctl-openapi: mock server of %s listening on %s\n", fs.Arg(0), *addr)
+	if err = http.ListenAndServe(*addr, handler); err != nil {
+		fmt.Printf("goctl-openapi: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,234 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/invopop/yaml"
+	"github.com/jayvynl/goctl-openapi/oas3"
+	"github.com/pkg/errors"
+)
+
+// FileNames are config file names discovered next to the api file.
+var FileNames = []string{".goctl-openapi.yaml", ".goctl-openapi.yml"}
+
+type (
+	// Config is the project configuration of generator options,
+	// options passed explicitly by command line flags take precedence.
+	Config struct {
+		Output  Output `json:"output"`
+		Version string `json:"version"`
+		// Merge are partial openapi files, relative paths are resolved against config file directory.
+		Merge []string `json:"merge"`
+		// Overlays are overlay files, relative paths are resolved against config file directory.
+		Overlays []string `json:"overlays"`
+		// Rules are transformation rules files, relative paths are resolved against config file directory.
+		Rules          []string                 `json:"rules"`
+		Security       *Security                `json:"security"`
+		Envelope       *Envelope                `json:"envelope"`
+		ErrorResponses map[string]ErrorResponse `json:"errorResponses"`
+		Naming         Naming                   `json:"naming"`
+		Filter         Filter                   `json:"filter"`
+		AllTypes       bool                     `json:"allTypes"`
+		// Examples synthesizes examples of schemas, parameters, request bodies and responses.
+		Examples bool `json:"examples"`
+		// ExampleDepth is how many times a recursive schema is expanded in examples.
+		ExampleDepth int `json:"exampleDepth"`
+	}
+
+	Output struct {
+		Filename string `json:"filename"`
+		Format   string `json:"format"`
+		Pretty   bool   `json:"pretty"`
+		Embed    string `json:"embed"`
+		// HTTP is the directory of http request files, relative to the output directory.
+		HTTP string `json:"http"`
+		// JSONSchema is the directory of json schema files, relative to the output directory.
+		JSONSchema string `json:"jsonschema"`
+		// TypeScript is the file name of typescript definitions, relative to the output directory.
+		TypeScript string `json:"typescript"`
+		// Contract is the directory of contract tests, relative to the output directory.
+		Contract string `json:"contract"`
+		// HeadingLevel is the heading level of document title in markdown format.
+		HeadingLevel int `json:"headingLevel"`
+	}
+
+	Security struct {
+		Name   string                   `json:"name"`
+		Global *bool                    `json:"global"`
+		Scheme *openapi3.SecurityScheme `json:"scheme"`
+	}
+
+	Envelope struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	}
+
+	ErrorResponse struct {
+		Description string `json:"description"`
+		Type        string `json:"type"`
+	}
+
+	Naming struct {
+		OperationID string `json:"operationId"`
+	}
+
+	Filter struct {
+		Include FilterRule `json:"include"`
+		Exclude FilterRule `json:"exclude"`
+	}
+
+	FilterRule struct {
+		Groups     []string `json:"groups"`
+		Tags       []string `json:"tags"`
+		Paths      []string `json:"paths"`
+		Handlers   []string `json:"handlers"`
+		Visibility []string `json:"visibility"`
+	}
+)
+
+// Find returns the config file in dir, empty string if not exists.
+func Find(dir string) string {
+	for _, name := range FileNames {
+		filename := filepath.Join(dir, name)
+		if _, err := os.Stat(filename); err == nil {
+			return filename
+		}
+	}
+	return ""
+}
+
+// Load reads and validates config file in yaml or json format.
+func Load(filename string) (*Config, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	c, err := Parse(data)
+	if err != nil {
+		return nil, errors.WithMessage(err, filename)
+	}
+
+	dir := filepath.Dir(filename)
+	for i, m := range c.Merge {
+		c.Merge[i] = ResolvePath(dir, m)
+	}
+	for i, o := range c.Overlays {
+		c.Overlays[i] = ResolvePath(dir, o)
+	}
+	for i, r := range c.Rules {
+		c.Rules[i] = ResolvePath(dir, r)
+	}
+	return c, nil
+}
+
+// Parse parses and validates config in yaml or json format.
+func Parse(data []byte) (*Config, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if err = json.Unmarshal(jsonData, &value); err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return &Config{}, nil
+	}
+	if err = Schema().VisitJSON(value, openapi3.MultiErrors()); err != nil {
+		return nil, errors.WithMessage(schemaError(err), "invalid config")
+	}
+
+	var c Config
+	if err = json.Unmarshal(jsonData, &c); err != nil {
+		return nil, err
+	}
+	if _, err = c.Options(); err != nil {
+		return nil, errors.WithMessage(err, "invalid config")
+	}
+	return &c, nil
+}
+
+// Options converts config to generator options.
+func (c *Config) Options() (oas3.Options, error) {
+	opts := oas3.DefaultOptions()
+	opts.Version = c.Version
+
+	if c.Security != nil {
+		if c.Security.Name != "" {
+			opts.Security.Name = c.Security.Name
+		}
+		if c.Security.Global != nil {
+			opts.Security.Global = *c.Security.Global
+		}
+		if c.Security.Scheme != nil {
+			opts.Security.Scheme = c.Security.Scheme
+		}
+	}
+
+	if c.Envelope != nil {
+		opts.Envelope = &oas3.EnvelopeOptions{
+			CodeField:    c.Envelope.Code,
+			MessageField: c.Envelope.Message,
+			DataField:    c.Envelope.Data,
+		}
+	}
+
+	if len(c.ErrorResponses) > 0 {
+		opts.ErrorResponses = make(map[int]oas3.ErrorResponse, len(c.ErrorResponses))
+		for status, er := range c.ErrorResponses {
+			code, err := strconv.Atoi(status)
+			if err != nil || code < 100 || code > 599 {
+				return opts, fmt.Errorf("errorResponses: invalid status code \"%s\"", status)
+			}
+			opts.ErrorResponses[code] = oas3.ErrorResponse{
+				Description: er.Description,
+				Type:        er.Type,
+			}
+		}
+	}
+
+	opts.AllTypes = c.AllTypes
+	opts.Naming.OperationID = c.Naming.OperationID
+	opts.Filter = oas3.FilterOptions{
+		Include: oas3.FilterRule(c.Filter.Include),
+		Exclude: oas3.FilterRule(c.Filter.Exclude),
+	}
+	return opts, opts.Validate()
+}
+
+// schemaError formats schema validation errors without schema and value details.
+func schemaError(err error) error {
+	var me openapi3.MultiError
+	if !errors.As(err, &me) {
+		me = openapi3.MultiError{err}
+	}
+
+	msgs := make([]string, 0, len(me))
+	for _, e := range me {
+		var se *openapi3.SchemaError
+		if errors.As(e, &se) {
+			msgs = append(msgs, fmt.Sprintf("\"/%s\": %s", strings.Join(se.JSONPointer(), "/"), se.Reason))
+		} else {
+			msgs = append(msgs, e.Error())
+		}
+	}
+	sort.Strings(msgs)
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+// ResolvePath resolves relative name against dir, absolute name is returned unchanged.
+func ResolvePath(dir, name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(dir, name)
+}
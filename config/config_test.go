@@ -0,0 +1,168 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{name: "empty", data: ""},
+		{name: "null", data: "null"},
+		{
+			name: "full",
+			data: `
+output:
+  filename: api
+  format: yaml
+  pretty: true
+  embed: internal/docs
+  http: http
+  contract: internal/handler
+  jsonschema: schemas
+  typescript: web/api.d.ts
+  headingLevel: 2
+version: v2
+merge: [extra.yaml]
+overlays: [gateway.yaml]
+rules: [rules.yaml]
+security:
+  name: apiKey
+  global: false
+  scheme: {type: apiKey, in: header, name: X-Api-Key}
+envelope: {code: code, message: msg, data: data}
+errorResponses:
+  "400": {description: Bad request, type: Error}
+naming: {operationId: snake}
+filter:
+  include: {groups: [book]}
+  exclude: {visibility: [internal]}
+allTypes: true
+examples: true
+exampleDepth: 2
+`,
+		},
+		{name: "json", data: `{"output": {"format": "json"}, "version": "v1"}`},
+		{name: "unknown key", data: "outputs: {}", err: `"/": property "outputs" is unsupported`},
+		{name: "unknown nested key", data: "output: {file: api}", err: `"/output": property "file" is unsupported`},
+		{name: "invalid format", data: "output: {format: xml}", err: `"/output/format": value is not one of the allowed values`},
+		{name: "invalid heading level", data: "output: {headingLevel: 5}", err: `"/output/headingLevel": number must be at most 4`},
+		{name: "invalid type", data: "merge: extra.yaml", err: `"/merge": value must be an array`},
+		{name: "empty security name", data: "security: {name: \"\"}", err: `"/security/name": minimum string length is 1`},
+		{name: "scheme without type", data: "security: {scheme: {scheme: bearer}}", err: `"/security/scheme/type": property "type" is missing`},
+		{name: "invalid scheme", data: "security: {scheme: {type: http}}", err: "invalid config: security scheme"},
+		{name: "invalid naming", data: "naming: {operationId: upper}", err: `"/naming/operationId": value is not one of the allowed values`},
+		{name: "invalid status code", data: "errorResponses: {abc: {type: Error}}", err: `errorResponses: invalid status code "abc"`},
+		{name: "status code out of range", data: "errorResponses: {\"600\": {type: Error}}", err: `errorResponses: invalid status code "600"`},
+		{name: "several errors", data: "version: 1\nallTypes: yes please", err: `"/allTypes": value must be a boolean; "/version": value must be a string`},
+		{name: "invalid yaml", data: "output: [", err: "yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("Parse() error = %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Parse() error = %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	c, err := Parse([]byte(`
+version: v2
+security: {name: apiKey, global: false, scheme: {type: apiKey, in: header, name: X-Api-Key}}
+envelope: {code: code, message: msg, data: data}
+errorResponses: {"404": {description: Not found, type: Error}}
+naming: {operationId: kebab}
+filter: {exclude: {groups: [admin], paths: [/internal/**]}}
+allTypes: true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := c.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"version", opts.Version, "v2"},
+		{"security name", opts.Security.Name, "apiKey"},
+		{"security global", opts.Security.Global, false},
+		{"security scheme", opts.Security.Scheme.In, "header"},
+		{"envelope message", opts.Envelope.MessageField, "msg"},
+		{"error response", opts.ErrorResponses[404].Type, "Error"},
+		{"naming", opts.Naming.OperationID, "kebab"},
+		{"filter groups", strings.Join(opts.Filter.Exclude.Groups, ","), "admin"},
+		{"filter paths", strings.Join(opts.Filter.Exclude.Paths, ","), "/internal/**"},
+		{"all types", opts.AllTypes, true},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// defaults are kept if config doesn't set them.
+	opts, err = (&Config{}).Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Security.Name != "jwt" || !opts.Security.Global || opts.Envelope != nil {
+		t.Errorf("default options changed: %+v", opts)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if got := Find(dir); got != "" {
+		t.Errorf("Find() = %s in empty directory", got)
+	}
+
+	filename := filepath.Join(dir, ".goctl-openapi.yml")
+	abs := filepath.Join(dir, "abs.yaml")
+	data := "merge: [extra.yaml, " + abs + "]\noverlays: [overlays/gateway.yaml]\nrules: [../rules.yaml]\n"
+	if err := os.WriteFile(filename, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := Find(dir); got != filename {
+		t.Errorf("Find() = %s, want %s", got, filename)
+	}
+
+	c, err := Load(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(dir, "extra.yaml"),
+		abs,
+		filepath.Join(dir, "overlays", "gateway.yaml"),
+		filepath.Join(filepath.Dir(dir), "rules.yaml"),
+	}
+	got := append(append(append([]string{}, c.Merge...), c.Overlays...), c.Rules...)
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("resolved paths = %v, want %v", got, want)
+	}
+
+	if err = os.WriteFile(filename, []byte("version: [v1]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Load(filename); err == nil || !strings.HasPrefix(err.Error(), filename+": invalid config") {
+		t.Errorf("Load() error = %v, want error prefixed by file name", err)
+	}
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Schema returns the schema of config file.
+func Schema() *openapi3.Schema {
+	stringList := openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema())
+	filterRule := openapi3.NewObjectSchema().
+		WithProperties(map[string]*openapi3.Schema{
+			"groups":     stringList,
+			"tags":       stringList,
+			"paths":      stringList,
+			"handlers":   stringList,
+			"visibility": stringList,
+		}).
+		WithoutAdditionalProperties()
+
+	return openapi3.NewObjectSchema().
+		WithProperties(map[string]*openapi3.Schema{
+			"output": openapi3.NewObjectSchema().
+				WithProperties(map[string]*openapi3.Schema{
+					"filename":     openapi3.NewStringSchema(),
+					"format":       openapi3.NewStringSchema().WithEnum("json", "yaml", "yml", "html", "markdown", "md", "postman", "jsonschema", "typescript", "ts"),
+					"pretty":       openapi3.NewBoolSchema(),
+					"embed":        openapi3.NewStringSchema(),
+					"http":         openapi3.NewStringSchema(),
+					"contract":     openapi3.NewStringSchema(),
+					"jsonschema":   openapi3.NewStringSchema(),
+					"typescript":   openapi3.NewStringSchema(),
+					"headingLevel": openapi3.NewIntegerSchema().WithMin(1).WithMax(4),
+				}).
+				WithoutAdditionalProperties(),
+			"version":  openapi3.NewStringSchema(),
+			"merge":    stringList,
+			"overlays": stringList,
+			"rules":    stringList,
+			"security": openapi3.NewObjectSchema().
+				WithProperties(map[string]*openapi3.Schema{
+					"name":   openapi3.NewStringSchema().WithMinLength(1),
+					"global": openapi3.NewBoolSchema(),
+					"scheme": openapi3.NewObjectSchema().
+						WithProperty("type", openapi3.NewStringSchema().WithEnum("apiKey", "http", "oauth2", "openIdConnect")).
+						WithRequired([]string{"type"}),
+				}).
+				WithoutAdditionalProperties(),
+			"envelope": openapi3.NewObjectSchema().
+				WithProperties(map[string]*openapi3.Schema{
+					"code":    openapi3.NewStringSchema().WithMinLength(1),
+					"message": openapi3.NewStringSchema().WithMinLength(1),
+					"data":    openapi3.NewStringSchema().WithMinLength(1),
+				}).
+				WithoutAdditionalProperties(),
+			"errorResponses": openapi3.NewObjectSchema().
+				WithAdditionalProperties(openapi3.NewObjectSchema().
+					WithProperties(map[string]*openapi3.Schema{
+						"description": openapi3.NewStringSchema(),
+						"type":        openapi3.NewStringSchema(),
+					}).
+					WithoutAdditionalProperties()),
+			"naming": openapi3.NewObjectSchema().
+				WithProperty("operationId", openapi3.NewStringSchema().WithEnum("", "camel", "pascal", "snake", "kebab")).
+				WithoutAdditionalProperties(),
+			"filter": openapi3.NewObjectSchema().
+				WithProperties(map[string]*openapi3.Schema{
+					"include": filterRule,
+					"exclude": filterRule,
+				}).
+				WithoutAdditionalProperties(),
+			"allTypes":     openapi3.NewBoolSchema(),
+			"examples":     openapi3.NewBoolSchema(),
+			"exampleDepth": openapi3.NewIntegerSchema().WithMin(1),
+		}).
+		WithoutAdditionalProperties()
+}
@@ -7,11 +7,19 @@ const (
 	ApiInfoVersion = "version"
 	ApiInfoAuthor  = "author"
 	ApiInfoEmail   = "email"
+	ApiInfoDate    = "date" // emitted as x-date extension of info
 	// extended openapi keys
 	// https://github.com/OAI/OpenAPI-Specification/blob/main/versions/3.0.3.md#fixed-fields
 	ApiInfoServers      = "servers"      // comma separated urls
 	ApiInfoExternalDocs = "externalDocs" // url
-	ApiInfoTags         = "tags"         // comma separated string
+	ApiInfoTags         = "tags"         // comma separated string, tags of operations in @doc or @server, tags of document in info
+	// route visibility used by filters, in @doc or @server
+	ApiInfoVisibility = "visibility" // e.g. "internal", emitted as x-visibility extension
+	ApiInfoInternal   = "internal"   // "true" is a shortcut of visibility "internal"
+
+	VisibilityInternal  = "internal"
+	ExtensionVisibility = "x-visibility"
+	ExtensionDate       = "x-date"
 
 	OptionDefault   = "default"
 	OptionOptional  = "optional"
@@ -25,4 +33,5 @@ const (
 	TagKeyJson   = "json"
 	// https://github.com/go-playground/validator
 	TagKeyValidate = "validate"
+	TagKeyExample  = "example"
 )
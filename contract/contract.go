@@ -0,0 +1,434 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jayvynl/goctl-openapi/internal/docutil"
+	"github.com/jayvynl/goctl-openapi/oas3"
+	"github.com/jayvynl/goctl-openapi/sample"
+)
+
+// HelperFile is the file name of shared test helpers.
+const HelperFile = "contract_helper_test.go"
+
+type (
+	// File is a generated go test file.
+	File struct {
+		// Name is the file name, e.g. "book_contract_test.go".
+		Name    string
+		Content []byte
+	}
+
+	// request is a test case of an operation.
+	request struct {
+		name        string
+		method      string
+		target      string
+		header      map[string]string
+		contentType string
+		body        string
+		secured     bool
+		invalid     bool
+	}
+
+	// values are parameters or body fields of a request.
+	values struct {
+		path   map[string]interface{}
+		query  map[string]interface{}
+		header map[string]interface{}
+		body   interface{}
+	}
+
+	// violation is a value violating constraints of a schema.
+	violation struct {
+		name  string
+		value interface{}
+		// missing removes the value from request.
+		missing bool
+	}
+)
+
+// Files generates go test files of package pkg, one file per group, operations are grouped by the first tag.
+// Every operation is tested with a valid request synthesized from schemas, and invalid requests
+// violating one constraint of a parameter or a body field each, e.g. minimum, enum or min length.
+// The tests call newContractServer, which is written by hand in the package.
+func Files(doc *openapi3.T, pkg string) ([]File, error) {
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string]*strings.Builder)
+	var names []string
+	paths := doc.Paths.InMatchingOrder()
+	sort.Strings(paths)
+	for _, path := range paths {
+		item := doc.Paths.Value(path)
+		for _, method := range docutil.Methods(item) {
+			op := item.GetOperation(method)
+			group := docutil.GroupTag(op.Tags)
+			sb, ok := groups[group]
+			if !ok {
+				sb = &strings.Builder{}
+				groups[group] = sb
+				names = append(names, group)
+			}
+			writeTest(sb, doc, path, method, item, op)
+		}
+	}
+
+	files := make([]File, 0, len(names)+1)
+	helper, err := format.Source([]byte(fmt.Sprintf(helperTemplate, pkg, strconv.Quote(string(spec)))))
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, File{Name: HelperFile, Content: helper})
+	sort.Strings(names)
+	for _, group := range names {
+		src := fmt.Sprintf("// Code generated by goctl-openapi. DO NOT EDIT.\n\npackage %s\n\nimport \"testing\"\n%s", pkg, groups[group].String())
+		content, err := format.Source([]byte(src))
+		if err != nil {
+			return nil, fmt.Errorf("format tests of group \"%s\": %w", group, err)
+		}
+		files = append(files, File{Name: docutil.FileName(group, docutil.DefaultTag) + "_contract_test.go", Content: content})
+	}
+	return files, nil
+}
+
+func writeTest(sb *strings.Builder, doc *openapi3.T, path, method string, item *openapi3.PathItem, op *openapi3.Operation) {
+	name := op.OperationID
+	if name == "" {
+		name = method + " " + path
+	}
+	fmt.Fprintf(sb, "\n// TestContract%s tests %s %s.\n", oas3.ConvertName("pascal", name), method, path)
+	fmt.Fprintf(sb, "func TestContract%s(t *testing.T) {\n", oas3.ConvertName("pascal", name))
+	sb.WriteString("\tcases := []contractCase{\n")
+	for _, r := range requests(doc, path, method, item, op) {
+		sb.WriteString("\t\t{\n")
+		fmt.Fprintf(sb, "name: %q,\nmethod: %q,\ntarget: %s,\n", r.name, r.method, goString(r.target))
+		if len(r.header) > 0 {
+			keys := make([]string, 0, len(r.header))
+			for k := range r.header {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			sb.WriteString("header: map[string]string{")
+			for i, k := range keys {
+				if i > 0 {
+					sb.WriteString(", ")
+				}
+				fmt.Fprintf(sb, "%q: %s", k, goString(r.header[k]))
+			}
+			sb.WriteString("},\n")
+		}
+		if r.contentType != "" {
+			fmt.Fprintf(sb, "contentType: %q,\nbody: %s,\n", r.contentType, goString(r.body))
+		}
+		if r.secured {
+			sb.WriteString("secured: true,\n")
+		}
+		if r.invalid {
+			sb.WriteString("invalid: true,\n")
+		}
+		sb.WriteString("},\n")
+	}
+	sb.WriteString("\t}\n")
+	sb.WriteString("\tfor _, c := range cases {\n\t\tc := c\n\t\tt.Run(c.name, func(t *testing.T) {\n\t\t\tcontractRun(t, c)\n\t\t})\n\t}\n}\n")
+}
+
+// requests returns the valid request and invalid requests of operation.
+func requests(doc *openapi3.T, path, method string, item *openapi3.PathItem, op *openapi3.Operation) []request {
+	params := append(openapi3.Parameters{}, op.Parameters...)
+	for _, ref := range item.Parameters {
+		if p := docutil.ResolveParameter(doc, ref); p != nil && op.Parameters.GetByInAndName(p.In, p.Name) == nil {
+			params = append(params, ref)
+		}
+	}
+
+	valid := values{
+		path:   make(map[string]interface{}),
+		query:  make(map[string]interface{}),
+		header: make(map[string]interface{}),
+	}
+	var invalid []func(v *values) string
+	for _, ref := range params {
+		p := docutil.ResolveParameter(doc, ref)
+		if p == nil {
+			continue
+		}
+		var m map[string]interface{}
+		switch p.In {
+		case openapi3.ParameterInPath:
+			m = valid.path
+		case openapi3.ParameterInQuery:
+			m = valid.query
+		case openapi3.ParameterInHeader:
+			m = valid.header
+		default:
+			continue
+		}
+		if p.Required {
+			m[p.Name] = sample.Value(doc, p.Schema)
+		}
+		in, name := p.In, p.Name
+		for _, vio := range violations(doc, p.Schema, p.Required && p.In != openapi3.ParameterInPath) {
+			vio := vio
+			invalid = append(invalid, func(v *values) string {
+				target := v.params(in)
+				if vio.missing {
+					delete(target, name)
+				} else {
+					target[name] = vio.value
+				}
+				return fmt.Sprintf("%s %s %s", in, name, vio.name)
+			})
+		}
+	}
+
+	var contentType string
+	if rb := docutil.ResolveRequestBody(doc, op.RequestBody); rb != nil {
+		for _, ct := range []string{"application/json", "application/x-www-form-urlencoded"} {
+			mt := rb.Content.Get(ct)
+			if mt == nil {
+				continue
+			}
+			contentType = ct
+			valid.body = sample.Value(doc, mt.Schema)
+			s := docutil.ResolveSchema(doc, mt.Schema)
+			if s == nil {
+				break
+			}
+			required := make(map[string]bool, len(s.Required))
+			for _, name := range s.Required {
+				required[name] = true
+			}
+			names := make([]string, 0, len(s.Properties))
+			for name := range s.Properties {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				name := name
+				for _, vio := range violations(doc, s.Properties[name], required[name]) {
+					vio := vio
+					invalid = append(invalid, func(v *values) string {
+						obj, ok := v.body.(map[string]interface{})
+						if !ok {
+							return ""
+						}
+						if vio.missing {
+							delete(obj, name)
+						} else {
+							obj[name] = vio.value
+						}
+						return fmt.Sprintf("body %s %s", name, vio.name)
+					})
+				}
+			}
+			break
+		}
+	}
+
+	security := doc.Security
+	if op.Security != nil {
+		security = *op.Security
+	}
+	secured := len(security) > 0 && len(security[0]) > 0
+
+	rs := []request{newRequest("valid", path, method, contentType, secured, valid)}
+	for _, mutate := range invalid {
+		v := valid.clone()
+		if name := mutate(&v); name != "" {
+			r := newRequest(name, path, method, contentType, secured, v)
+			r.invalid = true
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+func newRequest(name, path, method, contentType string, secured bool, v values) request {
+	r := request{name: name, method: method, contentType: contentType, secured: secured}
+	for k, value := range v.path {
+		path = strings.ReplaceAll(path, "{"+k+"}", url.PathEscape(docutil.FormatValue(value)))
+	}
+	query := make(url.Values)
+	for k, value := range v.query {
+		query.Set(k, docutil.FormatValue(value))
+	}
+	r.target = path
+	if len(query) > 0 {
+		r.target += "?" + query.Encode()
+	}
+	if len(v.header) > 0 {
+		r.header = make(map[string]string, len(v.header))
+		for k, value := range v.header {
+			r.header[k] = docutil.FormatValue(value)
+		}
+	}
+
+	switch contentType {
+	case "application/json":
+		data, _ := json.Marshal(v.body)
+		r.body = string(data)
+	case "application/x-www-form-urlencoded":
+		form := make(url.Values)
+		obj, _ := v.body.(map[string]interface{})
+		for k, value := range obj {
+			form.Set(k, docutil.FormatValue(value))
+		}
+		r.body = form.Encode()
+	}
+	return r
+}
+
+func (v *values) params(in string) map[string]interface{} {
+	switch in {
+	case openapi3.ParameterInPath:
+		return v.path
+	case openapi3.ParameterInQuery:
+		return v.query
+	default:
+		return v.header
+	}
+}
+
+// clone copies parameters and the top level fields of body.
+func (v values) clone() values {
+	c := values{path: copyMap(v.path), query: copyMap(v.query), header: copyMap(v.header), body: v.body}
+	if obj, ok := v.body.(map[string]interface{}); ok {
+		c.body = copyMap(obj)
+	}
+	return c
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// violations returns values violating constraints of schema, one constraint each.
+func violations(doc *openapi3.T, ref *openapi3.SchemaRef, required bool) []violation {
+	var vs []violation
+	if required {
+		vs = append(vs, violation{name: "missing", missing: true})
+	}
+	s := docutil.ResolveSchema(doc, ref)
+	if s == nil {
+		return vs
+	}
+	if len(s.AllOf) == 1 && s.Type == "" && len(s.Properties) == 0 {
+		// nullable reference like {"nullable": true, "allOf": [{"$ref": "..."}]}, non null values have constraints
+		// of the referenced schema.
+		if s = docutil.ResolveSchema(doc, s.AllOf[0]); s == nil {
+			return vs
+		}
+	}
+
+	if len(s.Enum) > 0 {
+		if v, ok := notInEnum(s); ok {
+			vs = append(vs, violation{name: "not in enum", value: v})
+		}
+		return vs
+	}
+	switch s.Type {
+	case openapi3.TypeInteger, openapi3.TypeNumber:
+		step := 1.0
+		if s.Type == openapi3.TypeNumber {
+			step = 0.5
+		}
+		if s.Min != nil {
+			v := *s.Min
+			if !s.ExclusiveMin {
+				v -= step
+			}
+			vs = append(vs, violation{name: "below minimum", value: number(s, v)})
+		}
+		if s.Max != nil {
+			v := *s.Max
+			if !s.ExclusiveMax {
+				v += step
+			}
+			vs = append(vs, violation{name: "above maximum", value: number(s, v)})
+		}
+	case openapi3.TypeString:
+		if s.MinLength > 0 {
+			vs = append(vs, violation{name: "shorter than min length", value: strings.Repeat("a", int(s.MinLength-1))})
+		}
+		if s.MaxLength != nil && *s.MaxLength < math.MaxInt32 {
+			vs = append(vs, violation{name: "longer than max length", value: strings.Repeat("a", int(*s.MaxLength+1))})
+		}
+	case openapi3.TypeArray:
+		item := sample.Value(doc, s.Items)
+		if s.MinItems > 0 {
+			vs = append(vs, violation{name: "fewer than min items", value: repeat(item, s.MinItems-1)})
+		}
+		if s.MaxItems != nil && *s.MaxItems < math.MaxInt16 {
+			vs = append(vs, violation{name: "more than max items", value: repeat(item, *s.MaxItems+1)})
+		}
+	}
+	return vs
+}
+
+// notInEnum returns a value of the same type as enum values, which is not in enum.
+func notInEnum(s *openapi3.Schema) (interface{}, bool) {
+	in := func(v interface{}) bool {
+		for _, e := range s.Enum {
+			if fmt.Sprint(e) == fmt.Sprint(v) {
+				return true
+			}
+		}
+		return false
+	}
+	switch s.Type {
+	case openapi3.TypeString:
+		v := "invalid"
+		for in(v) {
+			v += "_"
+		}
+		return v, true
+	case openapi3.TypeInteger, openapi3.TypeNumber:
+		v := 0.0
+		for _, e := range s.Enum {
+			if f, ok := e.(float64); ok && f >= v {
+				v = f + 1
+			}
+		}
+		return number(s, v), true
+	}
+	return nil, false
+}
+
+func number(s *openapi3.Schema, v float64) interface{} {
+	if s.Type == openapi3.TypeInteger {
+		return int64(v)
+	}
+	return v
+}
+
+func repeat(v interface{}, n uint64) []interface{} {
+	items := make([]interface{}, n)
+	for i := range items {
+		items[i] = v
+	}
+	return items
+}
+
+// goString returns go string literal of s, a raw string if possible.
+func goString(s string) string {
+	if strings.ContainsAny(s, "`\r") || !strconv.CanBackquote(s) {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
@@ -0,0 +1,158 @@
+package contract
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const spec = `
+openapi: 3.0.3
+info: {title: books, version: v1}
+security: [{jwt: []}]
+paths:
+  /books/{id}:
+    get:
+      tags: [book]
+      operationId: getBook
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer, minimum: 1}}
+        - {name: lang, in: query, schema: {type: string, enum: [en, zh]}}
+      responses: {"200": {description: ok}}
+  /books:
+    post:
+      tags: [book.v1]
+      security: []
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/Book"}
+      responses: {"200": {description: ok}}
+components:
+  securitySchemes:
+    jwt: {type: http, scheme: bearer}
+  schemas:
+    Author:
+      type: object
+      properties: {name: {type: string}}
+    Name: {type: string, minLength: 2, maxLength: 8}
+    Book:
+      type: object
+      required: [name]
+      properties:
+        name: {$ref: "#/components/schemas/Name"}
+        alias: {nullable: true, allOf: [{$ref: "#/components/schemas/Name"}]}
+        author: {nullable: true, allOf: [{$ref: "#/components/schemas/Author"}]}
+        tags: {type: array, items: {type: string}, minItems: 1, maxItems: 2}
+        price: {type: number, minimum: 0, exclusiveMinimum: true, maximum: 100}
+`
+
+func load(t *testing.T) *openapi3.T {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestViolations(t *testing.T) {
+	doc := load(t)
+	book := doc.Components.Schemas["Book"].Value
+	tests := []struct {
+		name     string
+		ref      *openapi3.SchemaRef
+		required bool
+		want     string
+	}{
+		{"required reference", book.Properties["name"], true, "missing, shorter than min length=a, longer than max length=aaaaaaaaa"},
+		{"nullable reference", book.Properties["alias"], false, "shorter than min length=a, longer than max length=aaaaaaaaa"},
+		{"nullable object", book.Properties["author"], false, ""},
+		{"array", book.Properties["tags"], false, "fewer than min items=[], more than max items=[string string string]"},
+		{"number", book.Properties["price"], false, "below minimum=0, above maximum=100.5"},
+		{"integer enum", openapi3.NewSchemaRef("", &openapi3.Schema{Type: "integer", Enum: []interface{}{float64(1), float64(3)}}), false, "not in enum=4"},
+		{"string enum", openapi3.NewSchemaRef("", &openapi3.Schema{Type: "string", Enum: []interface{}{"invalid"}}), false, "not in enum=invalid_"},
+		{"unresolved", openapi3.NewSchemaRef("#/components/schemas/Missing", nil), true, "missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, v := range violations(doc, tt.ref, tt.required) {
+				if v.missing {
+					got = append(got, v.name)
+				} else {
+					got = append(got, fmt.Sprintf("%s=%v", v.name, v.value))
+				}
+			}
+			if s := strings.Join(got, ", "); s != tt.want {
+				t.Errorf("violations() = %s, want %s", s, tt.want)
+			}
+		})
+	}
+}
+
+func TestFiles(t *testing.T) {
+	files, err := Files(load(t), "handler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := make(map[string]string)
+	var names []string
+	for _, f := range files {
+		if _, err := parser.ParseFile(token.NewFileSet(), f.Name, f.Content, 0); err != nil {
+			t.Errorf("%s is not valid go: %s", f.Name, err)
+		}
+		contents[f.Name] = string(f.Content)
+		names = append(names, f.Name)
+	}
+	if got := strings.Join(names, ","); got != HelperFile+",book_contract_test.go,book_v1_contract_test.go" {
+		t.Fatalf("files = %s", got)
+	}
+
+	tests := []struct {
+		name string
+		file string
+		want []string
+	}{
+		{"package", HelperFile, []string{"package handler\n"}},
+		{"test name", "book_contract_test.go", []string{"func TestContractGetBook(t *testing.T) {"}},
+		{"valid request", "book_contract_test.go", []string{"name:    \"valid\",\n\t\t\tmethod:  \"GET\",\n\t\t\ttarget:  `/books/1`,\n\t\t\tsecured: true,\n"}},
+		{"path violation", "book_contract_test.go", []string{"name:    \"path id below minimum\",\n\t\t\tmethod:  \"GET\",\n\t\t\ttarget:  `/books/0`,"}},
+		{"query violation", "book_contract_test.go", []string{"target:  `/books/1?lang=invalid`,"}},
+		{"body violation", "book_v1_contract_test.go", []string{
+			"func TestContractPostBooks(t *testing.T) {",
+			`name:        "body name missing",`,
+			`name:        "body alias shorter than min length",`,
+			`name:        "body price below minimum",`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, want := range tt.want {
+				if !strings.Contains(contents[tt.file], want) {
+					t.Errorf("%s doesn't contain %q:\n%s", tt.file, want, contents[tt.file])
+				}
+			}
+		})
+	}
+	if strings.Contains(contents["book_v1_contract_test.go"], "secured") {
+		t.Errorf("unsecured operation is tested with token")
+	}
+}
+
+func TestGoString(t *testing.T) {
+	for s, want := range map[string]string{
+		`{"a":1}`: "`{\"a\":1}`",
+		"a`b":     `"a` + "`" + `b"`,
+		"a\nb":    `"a\nb"`,
+		"a\rb":    `"a\rb"`,
+	} {
+		if got := goString(s); got != want {
+			t.Errorf("goString(%q) = %s, want %s", s, got, want)
+		}
+	}
+}
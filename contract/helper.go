@@ -0,0 +1,118 @@
+package contract
+
+// helperTemplate is the shared helpers of generated tests, formatted with package name and quoted document.
+const helperTemplate = `// Code generated by goctl-openapi. DO NOT EDIT.
+
+package %s
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+// contractSpec is the openapi document which handlers are tested against.
+const contractSpec = %s
+
+// contractToken is the bearer token of operations requiring security.
+var contractToken = os.Getenv("CONTRACT_TOKEN")
+
+// contractCase is a request of an operation, invalid requests violate one constraint.
+type contractCase struct {
+	name        string
+	method      string
+	target      string
+	header      map[string]string
+	contentType string
+	body        string
+	secured     bool
+	invalid     bool
+}
+
+var (
+	contractOnce   sync.Once
+	contractRouter routers.Router
+	contractErr    error
+)
+
+// contractRun sends request of c to the handler returned by newContractServer,
+// which is written by hand in this package, e.g. a rest.Server with routes registered by RegisterHandlers.
+// Invalid requests must be rejected with status 400, valid requests must not,
+// responses of documented status must conform to the document.
+func contractRun(t *testing.T, c contractCase) {
+	t.Helper()
+	contractOnce.Do(func() {
+		var doc *openapi3.T
+		doc, contractErr = openapi3.NewLoader().LoadFromData([]byte(contractSpec))
+		if contractErr != nil {
+			return
+		}
+		// routes are served at root, servers are ignored.
+		doc.Servers = nil
+		for _, item := range doc.Paths.Map() {
+			item.Servers = nil
+			for _, op := range item.Operations() {
+				op.Servers = nil
+			}
+		}
+		contractRouter, contractErr = legacy.NewRouter(doc)
+	})
+	if contractErr != nil {
+		t.Fatalf("load openapi document: %%s", contractErr)
+	}
+
+	req := httptest.NewRequest(c.method, c.target, strings.NewReader(c.body))
+	if c.contentType != "" {
+		req.Header.Set("Content-Type", c.contentType)
+	}
+	for k, v := range c.header {
+		req.Header.Set(k, v)
+	}
+	if c.secured && contractToken != "" {
+		req.Header.Set("Authorization", "Bearer "+contractToken)
+	}
+	rec := httptest.NewRecorder()
+	newContractServer(t).ServeHTTP(rec, req)
+
+	if c.invalid {
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("status %%d of invalid request, expect 400, body: %%s", rec.Code, rec.Body)
+		}
+		return
+	}
+	if rec.Code == http.StatusBadRequest {
+		t.Fatalf("valid request is rejected, body: %%s", rec.Body)
+	}
+
+	route, params, err := contractRouter.FindRoute(req)
+	if err != nil {
+		t.Fatalf("find route: %%s", err)
+	}
+	opts := &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc}
+	err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: params,
+			Route:      route,
+			Options:    opts,
+		},
+		Status:  rec.Code,
+		Header:  rec.Header(),
+		Body:    io.NopCloser(rec.Body),
+		Options: opts,
+	})
+	if err != nil {
+		t.Errorf("response of status %%d doesn't conform to the document: %%s", rec.Code, err)
+	}
+}
+`
@@ -0,0 +1,382 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jayvynl/goctl-openapi/internal/docutil"
+)
+
+type Severity string
+
+const (
+	// SeverityBreaking changes break existing clients.
+	SeverityBreaking Severity = "breaking"
+	// SeverityWarning changes may break some clients.
+	SeverityWarning Severity = "warning"
+	// SeverityInfo changes are compatible.
+	SeverityInfo Severity = "info"
+)
+
+// Rank orders severities, higher is more severe.
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityBreaking:
+		return 2
+	case SeverityWarning:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ParseSeverity parses severity name.
+func ParseSeverity(s string) (Severity, error) {
+	switch Severity(s) {
+	case SeverityBreaking, SeverityWarning, SeverityInfo:
+		return Severity(s), nil
+	}
+	return "", fmt.Errorf("invalid severity \"%s\", expect one of breaking, warning and info", s)
+}
+
+// Kinds of changes.
+const (
+	KindOperationAdded      = "operation-added"
+	KindOperationRemoved    = "operation-removed"
+	KindOperationDeprecated = "operation-deprecated"
+	KindSecurityChanged     = "security-changed"
+	KindParameterAdded      = "parameter-added"
+	KindParameterRemoved    = "parameter-removed"
+	KindParameterRequired   = "parameter-required"
+	KindParameterOptional   = "parameter-optional"
+	KindBodyAdded           = "body-added"
+	KindBodyRemoved         = "body-removed"
+	KindBodyRequired        = "body-required"
+	KindMediaTypeAdded      = "media-type-added"
+	KindMediaTypeRemoved    = "media-type-removed"
+	KindResponseAdded       = "response-added"
+	KindResponseRemoved     = "response-removed"
+	KindPropertyAdded       = "property-added"
+	KindPropertyRemoved     = "property-removed"
+	KindPropertyRequired    = "property-required"
+	KindPropertyOptional    = "property-optional"
+	KindPropertyDeprecated  = "property-deprecated"
+	KindTypeChanged         = "type-changed"
+	KindFormatChanged       = "format-changed"
+	KindNullableChanged     = "nullable-changed"
+	KindEnumNarrowed        = "enum-narrowed"
+	KindEnumWidened         = "enum-widened"
+	KindConstraintTightened = "constraint-tightened"
+	KindConstraintRelaxed   = "constraint-relaxed"
+	KindDefaultChanged      = "default-changed"
+)
+
+// Change is a difference between two documents.
+type Change struct {
+	Severity    Severity `json:"severity"`
+	Kind        string   `json:"kind"`
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	OperationID string   `json:"operationId,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	// Location in the operation, e.g. "query.page", "body.author.books[].name", "response.200.body.name".
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (c Change) String() string {
+	s := fmt.Sprintf("%-8s %s %s", strings.ToUpper(string(c.Severity)), c.Method, c.Path)
+	if c.Location != "" {
+		s += " " + c.Location
+	}
+	return s + ": " + c.Message
+}
+
+// Compare compares operations of base and revision documents, changes are sorted by severity, path and method.
+func Compare(base, revision *openapi3.T) []Change {
+	c := &comparer{
+		base:     base,
+		revision: revision,
+		visited:  make(map[visitKey]bool),
+		nullable: make(map[*openapi3.Schema]*openapi3.Schema),
+	}
+	baseOps := operations(base)
+	revOps := operations(revision)
+
+	for key, bo := range baseOps {
+		ro, ok := revOps[key]
+		if !ok {
+			c.add(bo, SeverityBreaking, KindOperationRemoved, "", "operation removed")
+			continue
+		}
+		c.compareOperation(bo, ro)
+	}
+	for key, ro := range revOps {
+		if _, ok := baseOps[key]; !ok {
+			c.add(ro, SeverityInfo, KindOperationAdded, "", "operation added")
+		}
+	}
+
+	sort.SliceStable(c.changes, func(i, j int) bool {
+		a, b := c.changes[i], c.changes[j]
+		if a.Severity != b.Severity {
+			return a.Severity.Rank() > b.Severity.Rank()
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		return a.Message < b.Message
+	})
+	return c.changes
+}
+
+// Count counts changes by severity.
+func Count(changes []Change) map[Severity]int {
+	count := map[Severity]int{
+		SeverityBreaking: 0,
+		SeverityWarning:  0,
+		SeverityInfo:     0,
+	}
+	for _, c := range changes {
+		count[c.Severity]++
+	}
+	return count
+}
+
+type (
+	operation struct {
+		method   string
+		path     string
+		value    *openapi3.Operation
+		security openapi3.SecurityRequirements
+	}
+
+	// operationKey identifies an operation, path parameter names are ignored.
+	operationKey struct {
+		method string
+		path   string
+	}
+
+	visitKey struct {
+		base, revision *openapi3.Schema
+		request        bool
+	}
+
+	comparer struct {
+		base, revision *openapi3.T
+		changes        []Change
+		// schemas being compared, breaks cycle of recursive schemas
+		visited map[visitKey]bool
+		// resolved nullable references
+		nullable map[*openapi3.Schema]*openapi3.Schema
+	}
+)
+
+var pathParamRe = regexp.MustCompile(`\{[^}]*}`)
+
+func operations(doc *openapi3.T) map[operationKey]operation {
+	ops := make(map[operationKey]operation)
+	if doc.Paths == nil {
+		return ops
+	}
+	for path, item := range doc.Paths.Map() {
+		for method, op := range item.Operations() {
+			security := doc.Security
+			if op.Security != nil {
+				security = *op.Security
+			}
+			ops[operationKey{method: method, path: pathParamRe.ReplaceAllString(path, "{}")}] = operation{
+				method:   method,
+				path:     path,
+				value:    op,
+				security: security,
+			}
+		}
+	}
+	return ops
+}
+
+func (c *comparer) add(op operation, severity Severity, kind, location, format string, args ...interface{}) {
+	c.changes = append(c.changes, Change{
+		Severity:    severity,
+		Kind:        kind,
+		Method:      op.method,
+		Path:        op.path,
+		OperationID: op.value.OperationID,
+		Summary:     op.value.Summary,
+		Tags:        op.value.Tags,
+		Location:    location,
+		Message:     fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *comparer) compareOperation(bo, ro operation) {
+	if !bo.value.Deprecated && ro.value.Deprecated {
+		c.add(ro, SeverityWarning, KindOperationDeprecated, "", "operation deprecated")
+	}
+	if len(bo.security) == 0 && len(ro.security) > 0 {
+		c.add(ro, SeverityBreaking, KindSecurityChanged, "", "authentication is required")
+	} else if len(bo.security) > 0 && len(ro.security) == 0 {
+		c.add(ro, SeverityInfo, KindSecurityChanged, "", "authentication is not required")
+	}
+
+	c.compareParameters(bo, ro)
+	c.compareRequestBody(bo, ro)
+	c.compareResponses(bo, ro)
+}
+
+func (c *comparer) compareParameters(bo, ro operation) {
+	type paramKey struct{ in, name string }
+	// path parameters are identified by position, renaming them is compatible.
+	keyOf := func(op operation, p *openapi3.Parameter) paramKey {
+		if p.In == openapi3.ParameterInPath {
+			for i, m := range pathParamRe.FindAllString(op.path, -1) {
+				if m[1:len(m)-1] == p.Name {
+					return paramKey{in: p.In, name: fmt.Sprintf("#%d", i)}
+				}
+			}
+		}
+		return paramKey{in: p.In, name: p.Name}
+	}
+	baseParams := make(map[paramKey]*openapi3.Parameter)
+	for _, p := range bo.value.Parameters {
+		if v := docutil.ResolveParameter(c.base, p); v != nil {
+			baseParams[keyOf(bo, v)] = v
+		}
+	}
+	revParams := make(map[paramKey]*openapi3.Parameter)
+	for _, p := range ro.value.Parameters {
+		if v := docutil.ResolveParameter(c.revision, p); v != nil {
+			revParams[keyOf(ro, v)] = v
+		}
+	}
+
+	for key, bp := range baseParams {
+		loc := key.in + "." + bp.Name
+		rp, ok := revParams[key]
+		if !ok {
+			if key.in == openapi3.ParameterInPath {
+				c.add(ro, SeverityBreaking, KindParameterRemoved, loc, "path parameter removed")
+			} else {
+				c.add(ro, SeverityWarning, KindParameterRemoved, loc, "parameter removed, it will be ignored")
+			}
+			continue
+		}
+		if !bp.Required && rp.Required {
+			c.add(ro, SeverityBreaking, KindParameterRequired, loc, "parameter became required")
+		} else if bp.Required && !rp.Required {
+			c.add(ro, SeverityInfo, KindParameterOptional, loc, "parameter became optional")
+		}
+		if !bp.Deprecated && rp.Deprecated {
+			c.add(ro, SeverityInfo, KindPropertyDeprecated, loc, "parameter deprecated")
+		}
+		c.compareSchema(bo, ro, loc, true, bp.Schema, rp.Schema)
+	}
+	for key, rp := range revParams {
+		if _, ok := baseParams[key]; ok {
+			continue
+		}
+		loc := key.in + "." + rp.Name
+		if rp.Required {
+			c.add(ro, SeverityBreaking, KindParameterAdded, loc, "required parameter added")
+		} else {
+			c.add(ro, SeverityInfo, KindParameterAdded, loc, "optional parameter added")
+		}
+	}
+}
+
+func (c *comparer) compareRequestBody(bo, ro operation) {
+	bb := docutil.ResolveRequestBody(c.base, bo.value.RequestBody)
+	rb := docutil.ResolveRequestBody(c.revision, ro.value.RequestBody)
+	switch {
+	case bb == nil && rb == nil:
+		return
+	case bb == nil:
+		if rb.Required {
+			c.add(ro, SeverityBreaking, KindBodyAdded, "body", "required request body added")
+		} else {
+			c.add(ro, SeverityInfo, KindBodyAdded, "body", "optional request body added")
+		}
+		return
+	case rb == nil:
+		c.add(ro, SeverityWarning, KindBodyRemoved, "body", "request body removed, it will be ignored")
+		return
+	}
+
+	if !bb.Required && rb.Required {
+		c.add(ro, SeverityBreaking, KindBodyRequired, "body", "request body became required")
+	}
+	for mt, bm := range bb.Content {
+		rm, ok := rb.Content[mt]
+		if !ok {
+			c.add(ro, SeverityBreaking, KindMediaTypeRemoved, "body", "media type \"%s\" removed", mt)
+			continue
+		}
+		c.compareSchema(bo, ro, "body", true, bm.Schema, rm.Schema)
+	}
+	for mt := range rb.Content {
+		if _, ok := bb.Content[mt]; !ok {
+			c.add(ro, SeverityInfo, KindMediaTypeAdded, "body", "media type \"%s\" added", mt)
+		}
+	}
+}
+
+func (c *comparer) compareResponses(bo, ro operation) {
+	var baseResponses, revResponses map[string]*openapi3.ResponseRef
+	if bo.value.Responses != nil {
+		baseResponses = bo.value.Responses.Map()
+	}
+	if ro.value.Responses != nil {
+		revResponses = ro.value.Responses.Map()
+	}
+
+	for status, bref := range baseResponses {
+		loc := "response." + status
+		rref, ok := revResponses[status]
+		if !ok {
+			if isSuccess(status) {
+				c.add(ro, SeverityBreaking, KindResponseRemoved, loc, "response removed")
+			} else {
+				c.add(ro, SeverityWarning, KindResponseRemoved, loc, "response removed")
+			}
+			continue
+		}
+		br := docutil.ResolveResponse(c.base, bref)
+		rr := docutil.ResolveResponse(c.revision, rref)
+		if br == nil || rr == nil {
+			continue
+		}
+		for mt, bm := range br.Content {
+			rm, ok := rr.Content[mt]
+			if !ok {
+				c.add(ro, SeverityBreaking, KindMediaTypeRemoved, loc, "media type \"%s\" removed", mt)
+				continue
+			}
+			c.compareSchema(bo, ro, loc+".body", false, bm.Schema, rm.Schema)
+		}
+		for mt := range rr.Content {
+			if _, ok := br.Content[mt]; !ok {
+				c.add(ro, SeverityInfo, KindMediaTypeAdded, loc, "media type \"%s\" added", mt)
+			}
+		}
+	}
+	for status := range revResponses {
+		if _, ok := baseResponses[status]; !ok {
+			c.add(ro, SeverityInfo, KindResponseAdded, "response."+status, "response added")
+		}
+	}
+}
+
+func isSuccess(status string) bool {
+	return strings.HasPrefix(status, "2") || status == "default"
+}
@@ -0,0 +1,264 @@
+package diff
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// load loads a document with paths and schemas components.
+func load(t *testing.T, paths, schemas string) *openapi3.T {
+	t.Helper()
+	if schemas == "" {
+		schemas = "{}"
+	}
+	data := fmt.Sprintf(`{
+		"openapi": "3.0.3",
+		"info": {"title": "test", "version": "1.0"},
+		"paths": %s,
+		"components": {"schemas": %s}
+	}`, paths, schemas)
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// bodyOp returns paths with a POST /users operation whose request body and 200 response have the given schemas.
+func bodyOp(request, response string) string {
+	return fmt.Sprintf(`{"/users": {"post": {
+		"requestBody": {"required": true, "content": {"application/json": {"schema": %s}}},
+		"responses": {"200": {"description": "", "content": {"application/json": {"schema": %s}}}}
+	}}}`, request, response)
+}
+
+// paramOp returns paths with a GET /users/{id} operation having parameters.
+func paramOp(params string) string {
+	return fmt.Sprintf(`{"/users/{id}": {"get": {
+		"parameters": %s,
+		"responses": {"200": {"description": ""}}
+	}}}`, params)
+}
+
+const (
+	idParam   = `{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}`
+	pageParam = `{"name": "page", "in": "query", "schema": {"type": "integer"}}`
+	object    = `{"type": "object", "properties": {"name": {"type": "string"}}}`
+)
+
+func TestCompare(t *testing.T) {
+	type change struct {
+		severity Severity
+		kind     string
+		location string
+	}
+	tests := []struct {
+		name                           string
+		basePaths, baseSchemas         string
+		revisionPaths, revisionSchemas string
+		want                           []change
+	}{
+		{
+			name:          "identical",
+			basePaths:     bodyOp(object, object),
+			revisionPaths: bodyOp(object, object),
+		},
+		{
+			name:          "operation removed",
+			basePaths:     bodyOp(object, object),
+			revisionPaths: `{}`,
+			want:          []change{{SeverityBreaking, KindOperationRemoved, ""}},
+		},
+		{
+			name:          "operation added",
+			basePaths:     `{}`,
+			revisionPaths: bodyOp(object, object),
+			want:          []change{{SeverityInfo, KindOperationAdded, ""}},
+		},
+		{
+			name:          "path parameter renamed",
+			basePaths:     paramOp(`[` + idParam + `]`),
+			revisionPaths: `{"/users/{userId}": {"get": {"parameters": [{"name": "userId", "in": "path", "required": true, "schema": {"type": "integer"}}], "responses": {"200": {"description": ""}}}}}`,
+		},
+		{
+			name:          "path parameter type changed",
+			basePaths:     paramOp(`[` + idParam + `]`),
+			revisionPaths: paramOp(`[{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}]`),
+			want:          []change{{SeverityBreaking, KindTypeChanged, "path.id"}},
+		},
+		{
+			name:          "query parameter removed",
+			basePaths:     paramOp(`[` + idParam + `,` + pageParam + `]`),
+			revisionPaths: paramOp(`[` + idParam + `]`),
+			want:          []change{{SeverityWarning, KindParameterRemoved, "query.page"}},
+		},
+		{
+			name:          "optional parameter added",
+			basePaths:     paramOp(`[` + idParam + `]`),
+			revisionPaths: paramOp(`[` + idParam + `,` + pageParam + `]`),
+			want:          []change{{SeverityInfo, KindParameterAdded, "query.page"}},
+		},
+		{
+			name:          "required parameter added",
+			basePaths:     paramOp(`[` + idParam + `]`),
+			revisionPaths: paramOp(`[` + idParam + `,{"name": "page", "in": "query", "required": true, "schema": {"type": "integer"}}]`),
+			want:          []change{{SeverityBreaking, KindParameterAdded, "query.page"}},
+		},
+		{
+			name:          "parameter became required",
+			basePaths:     paramOp(`[` + idParam + `,` + pageParam + `]`),
+			revisionPaths: paramOp(`[` + idParam + `,{"name": "page", "in": "query", "required": true, "schema": {"type": "integer"}}]`),
+			want:          []change{{SeverityBreaking, KindParameterRequired, "query.page"}},
+		},
+		{
+			name:          "request property became required",
+			basePaths:     bodyOp(object, object),
+			revisionPaths: bodyOp(`{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}`, object),
+			want:          []change{{SeverityBreaking, KindPropertyRequired, "body.name"}},
+		},
+		{
+			name:          "required request property added",
+			basePaths:     bodyOp(object, object),
+			revisionPaths: bodyOp(`{"type": "object", "required": ["age"], "properties": {"name": {"type": "string"}, "age": {"type": "integer"}}}`, object),
+			want:          []change{{SeverityBreaking, KindPropertyAdded, "body.age"}},
+		},
+		{
+			name:          "response property removed",
+			basePaths:     bodyOp(object, object),
+			revisionPaths: bodyOp(object, `{"type": "object"}`),
+			want:          []change{{SeverityBreaking, KindPropertyRemoved, "response.200.body.name"}},
+		},
+		{
+			name:          "request property removed",
+			basePaths:     bodyOp(object, object),
+			revisionPaths: bodyOp(`{"type": "object"}`, object),
+			want:          []change{{SeverityWarning, KindPropertyRemoved, "body.name"}},
+		},
+		{
+			name:          "response type changed",
+			basePaths:     bodyOp(object, object),
+			revisionPaths: bodyOp(object, `{"type": "array", "items": {"type": "string"}}`),
+			want:          []change{{SeverityBreaking, KindTypeChanged, "response.200.body"}},
+		},
+		{
+			name:          "request enum narrowed",
+			basePaths:     bodyOp(`{"type": "string", "enum": ["a", "b"]}`, object),
+			revisionPaths: bodyOp(`{"type": "string", "enum": ["a"]}`, object),
+			want:          []change{{SeverityBreaking, KindEnumNarrowed, "body"}},
+		},
+		{
+			name:          "response enum widened",
+			basePaths:     bodyOp(object, `{"type": "string", "enum": ["a"]}`),
+			revisionPaths: bodyOp(object, `{"type": "string", "enum": ["a", "b"]}`),
+			want:          []change{{SeverityWarning, KindEnumWidened, "response.200.body"}},
+		},
+		{
+			name:          "request minimum raised",
+			basePaths:     bodyOp(`{"type": "integer", "minimum": 1}`, object),
+			revisionPaths: bodyOp(`{"type": "integer", "minimum": 5}`, object),
+			want:          []change{{SeverityBreaking, KindConstraintTightened, "body"}},
+		},
+		{
+			name:          "request maximum became exclusive",
+			basePaths:     bodyOp(`{"type": "integer", "maximum": 10}`, object),
+			revisionPaths: bodyOp(`{"type": "integer", "maximum": 10, "exclusiveMaximum": true}`, object),
+			want:          []change{{SeverityBreaking, KindConstraintTightened, "body"}},
+		},
+		{
+			name:          "request maximum removed",
+			basePaths:     bodyOp(`{"type": "integer", "maximum": 10}`, object),
+			revisionPaths: bodyOp(`{"type": "integer"}`, object),
+			want:          []change{{SeverityInfo, KindConstraintRelaxed, "body"}},
+		},
+		{
+			name:          "request maxLength added",
+			basePaths:     bodyOp(`{"type": "string"}`, object),
+			revisionPaths: bodyOp(`{"type": "string", "maxLength": 5}`, object),
+			want:          []change{{SeverityBreaking, KindConstraintTightened, "body"}},
+		},
+		{
+			name:          "response minItems raised",
+			basePaths:     bodyOp(object, `{"type": "array", "items": {"type": "string"}}`),
+			revisionPaths: bodyOp(object, `{"type": "array", "minItems": 1, "items": {"type": "string"}}`),
+			want:          []change{{SeverityInfo, KindConstraintTightened, "response.200.body"}},
+		},
+		{
+			name:          "request default changed",
+			basePaths:     bodyOp(`{"type": "integer", "default": 1}`, object),
+			revisionPaths: bodyOp(`{"type": "integer", "default": 2}`, object),
+			want:          []change{{SeverityWarning, KindDefaultChanged, "body"}},
+		},
+		{
+			name:          "response became nullable",
+			basePaths:     bodyOp(object, object),
+			revisionPaths: bodyOp(object, `{"type": "object", "nullable": true, "properties": {"name": {"type": "string"}}}`),
+			want:          []change{{SeverityWarning, KindNullableChanged, "response.200.body"}},
+		},
+		{
+			name:            "nullable reference is compared with referenced schema",
+			basePaths:       bodyOp(object, `{"$ref": "#/components/schemas/User"}`),
+			baseSchemas:     `{"User": ` + object + `}`,
+			revisionPaths:   bodyOp(object, `{"nullable": true, "allOf": [{"$ref": "#/components/schemas/User"}]}`),
+			revisionSchemas: `{"User": ` + object + `}`,
+			want:            []change{{SeverityWarning, KindNullableChanged, "response.200.body"}},
+		},
+		{
+			name:            "recursive schema",
+			basePaths:       bodyOp(object, `{"$ref": "#/components/schemas/Node"}`),
+			baseSchemas:     `{"Node": {"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#/components/schemas/Node"}}}}}`,
+			revisionPaths:   bodyOp(object, `{"$ref": "#/components/schemas/Node"}`),
+			revisionSchemas: `{"Node": {"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#/components/schemas/Node"}}, "name": {"type": "string"}}}}`,
+			want:            []change{{SeverityInfo, KindPropertyAdded, "response.200.body.name"}},
+		},
+		{
+			name:          "authentication required",
+			basePaths:     paramOp(`[` + idParam + `]`),
+			revisionPaths: `{"/users/{id}": {"get": {"security": [{"jwt": []}], "parameters": [` + idParam + `], "responses": {"200": {"description": ""}}}}}`,
+			want:          []change{{SeverityBreaking, KindSecurityChanged, ""}},
+		},
+		{
+			name:          "success response removed",
+			basePaths:     paramOp(`[` + idParam + `]`),
+			revisionPaths: `{"/users/{id}": {"get": {"parameters": [` + idParam + `], "responses": {"201": {"description": ""}}}}}`,
+			want: []change{
+				{SeverityBreaking, KindResponseRemoved, "response.200"},
+				{SeverityInfo, KindResponseAdded, "response.201"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := load(t, tt.basePaths, tt.baseSchemas)
+			revision := load(t, tt.revisionPaths, tt.revisionSchemas)
+			var got []change
+			for _, c := range Compare(base, revision) {
+				got = append(got, change{c.Severity, c.Kind, c.Location})
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("changes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	for _, s := range []Severity{SeverityBreaking, SeverityWarning, SeverityInfo} {
+		got, err := ParseSeverity(string(s))
+		if err != nil || got != s {
+			t.Errorf("ParseSeverity(%q) = %q, %v", s, got, err)
+		}
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Error("ParseSeverity(\"fatal\") should fail")
+	}
+}
+
+func TestCount(t *testing.T) {
+	got := Count([]Change{{Severity: SeverityBreaking}, {Severity: SeverityBreaking}, {Severity: SeverityInfo}})
+	want := map[Severity]int{SeverityBreaking: 2, SeverityWarning: 0, SeverityInfo: 1}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Count() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,285 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jayvynl/goctl-openapi/internal/docutil"
+)
+
+// compareSchema compares schemas at location, request schemas are sent by clients,
+// otherwise schemas are received by clients, the same change has opposite compatibility.
+func (c *comparer) compareSchema(bo, ro operation, loc string, request bool, baseRef, revRef *openapi3.SchemaRef) {
+	b := c.resolveSchema(c.base, baseRef)
+	r := c.resolveSchema(c.revision, revRef)
+	if b == nil || r == nil {
+		return
+	}
+	key := visitKey{base: b, revision: r, request: request}
+	if c.visited[key] {
+		return
+	}
+	c.visited[key] = true
+	defer delete(c.visited, key)
+
+	// severity of a change which restricts values, for request it breaks clients sending old values,
+	// for response clients can handle them.
+	narrowed, widened := SeverityBreaking, SeverityInfo
+	if !request {
+		narrowed, widened = SeverityInfo, SeverityWarning
+	}
+
+	if b.Type != r.Type {
+		c.add(ro, SeverityBreaking, KindTypeChanged, loc, "type changed from \"%s\" to \"%s\"", b.Type, r.Type)
+		return
+	}
+	if b.Format != r.Format {
+		c.add(ro, SeverityWarning, KindFormatChanged, loc, "format changed from \"%s\" to \"%s\"", b.Format, r.Format)
+	}
+	if b.Nullable && !r.Nullable {
+		c.add(ro, narrowed, KindNullableChanged, loc, "no longer nullable")
+	} else if !b.Nullable && r.Nullable {
+		c.add(ro, widened, KindNullableChanged, loc, "became nullable")
+	}
+	if !b.Deprecated && r.Deprecated {
+		c.add(ro, SeverityInfo, KindPropertyDeprecated, loc, "deprecated")
+	}
+	if request && !reflect.DeepEqual(b.Default, r.Default) {
+		c.add(ro, SeverityWarning, KindDefaultChanged, loc, "default changed from %s to %s", formatValue(b.Default), formatValue(r.Default))
+	}
+
+	c.compareEnum(ro, loc, narrowed, widened, b.Enum, r.Enum)
+	c.compareNumberRange(ro, loc, narrowed, widened, b, r)
+	c.compareCount(ro, loc, narrowed, widened, "minLength", "maxLength", b.MinLength, r.MinLength, b.MaxLength, r.MaxLength)
+	c.compareCount(ro, loc, narrowed, widened, "minItems", "maxItems", b.MinItems, r.MinItems, b.MaxItems, r.MaxItems)
+	c.compareCount(ro, loc, narrowed, widened, "minProperties", "maxProperties", b.MinProps, r.MinProps, b.MaxProps, r.MaxProps)
+	if b.Pattern != r.Pattern {
+		c.add(ro, SeverityWarning, KindConstraintTightened, loc, "pattern changed from \"%s\" to \"%s\"", b.Pattern, r.Pattern)
+	}
+
+	if b.Items != nil && r.Items != nil {
+		c.compareSchema(bo, ro, loc+"[]", request, b.Items, r.Items)
+	}
+	if b.AdditionalProperties.Schema != nil && r.AdditionalProperties.Schema != nil {
+		c.compareSchema(bo, ro, loc+"{}", request, b.AdditionalProperties.Schema, r.AdditionalProperties.Schema)
+	}
+	c.compareProperties(bo, ro, loc, request, b, r)
+}
+
+// resolveSchema resolves schema reference, a nullable reference like {"nullable": true, "allOf": [{"$ref": "..."}]}
+// is resolved to a nullable copy of the referenced schema, copies are reused for breaking cycle of recursive schemas.
+func (c *comparer) resolveSchema(doc *openapi3.T, ref *openapi3.SchemaRef) *openapi3.Schema {
+	s := docutil.ResolveSchema(doc, ref)
+	if s == nil || len(s.AllOf) != 1 || s.Type != "" || len(s.Properties) != 0 {
+		return s
+	}
+	if resolved, ok := c.nullable[s]; ok {
+		return resolved
+	}
+	target := c.resolveSchema(doc, s.AllOf[0])
+	if target == nil {
+		return s
+	}
+	resolved := *target
+	resolved.Nullable = resolved.Nullable || s.Nullable
+	resolved.Deprecated = resolved.Deprecated || s.Deprecated
+	if s.Description != "" {
+		resolved.Description = s.Description
+	}
+	c.nullable[s] = &resolved
+	return &resolved
+}
+
+func (c *comparer) compareProperties(bo, ro operation, loc string, request bool, b, r *openapi3.Schema) {
+	baseRequired := toSet(b.Required)
+	revRequired := toSet(r.Required)
+
+	for _, name := range sortedKeys(b.Properties) {
+		ploc := loc + "." + name
+		rp, ok := r.Properties[name]
+		if !ok {
+			if request {
+				c.add(ro, SeverityWarning, KindPropertyRemoved, ploc, "property removed, it will be ignored")
+			} else {
+				c.add(ro, SeverityBreaking, KindPropertyRemoved, ploc, "property removed")
+			}
+			continue
+		}
+		switch {
+		case !baseRequired[name] && revRequired[name]:
+			if request {
+				c.add(ro, SeverityBreaking, KindPropertyRequired, ploc, "property became required")
+			} else {
+				c.add(ro, SeverityInfo, KindPropertyRequired, ploc, "property became required")
+			}
+		case baseRequired[name] && !revRequired[name]:
+			if request {
+				c.add(ro, SeverityInfo, KindPropertyOptional, ploc, "property became optional")
+			} else {
+				c.add(ro, SeverityWarning, KindPropertyOptional, ploc, "property became optional, it may be absent")
+			}
+		}
+		c.compareSchema(bo, ro, ploc, request, b.Properties[name], rp)
+	}
+
+	for _, name := range sortedKeys(r.Properties) {
+		if _, ok := b.Properties[name]; ok {
+			continue
+		}
+		ploc := loc + "." + name
+		if request && revRequired[name] {
+			c.add(ro, SeverityBreaking, KindPropertyAdded, ploc, "required property added")
+		} else {
+			c.add(ro, SeverityInfo, KindPropertyAdded, ploc, "property added")
+		}
+	}
+}
+
+func (c *comparer) compareEnum(ro operation, loc string, narrowed, widened Severity, b, r []interface{}) {
+	switch {
+	case len(b) == 0 && len(r) == 0:
+		return
+	case len(b) == 0:
+		c.add(ro, narrowed, KindEnumNarrowed, loc, "values restricted to %s", formatValue(r))
+		return
+	case len(r) == 0:
+		c.add(ro, widened, KindEnumWidened, loc, "values no longer restricted")
+		return
+	}
+
+	removed := enumDiff(b, r)
+	added := enumDiff(r, b)
+	if len(removed) > 0 {
+		c.add(ro, narrowed, KindEnumNarrowed, loc, "enum values removed: %s", formatValue(removed))
+	}
+	if len(added) > 0 {
+		c.add(ro, widened, KindEnumWidened, loc, "enum values added: %s", formatValue(added))
+	}
+}
+
+func (c *comparer) compareNumberRange(ro operation, loc string, narrowed, widened Severity, b, r *openapi3.Schema) {
+	// compare minimum
+	if cmp := compareBound(b.Min, b.ExclusiveMin, r.Min, r.ExclusiveMin, false); cmp > 0 {
+		c.add(ro, narrowed, KindConstraintTightened, loc, "minimum changed from %s to %s", formatBound(b.Min, b.ExclusiveMin), formatBound(r.Min, r.ExclusiveMin))
+	} else if cmp < 0 {
+		c.add(ro, widened, KindConstraintRelaxed, loc, "minimum changed from %s to %s", formatBound(b.Min, b.ExclusiveMin), formatBound(r.Min, r.ExclusiveMin))
+	}
+	// compare maximum
+	if cmp := compareBound(b.Max, b.ExclusiveMax, r.Max, r.ExclusiveMax, true); cmp > 0 {
+		c.add(ro, narrowed, KindConstraintTightened, loc, "maximum changed from %s to %s", formatBound(b.Max, b.ExclusiveMax), formatBound(r.Max, r.ExclusiveMax))
+	} else if cmp < 0 {
+		c.add(ro, widened, KindConstraintRelaxed, loc, "maximum changed from %s to %s", formatBound(b.Max, b.ExclusiveMax), formatBound(r.Max, r.ExclusiveMax))
+	}
+}
+
+// compareBound returns 1 if revision bound is tighter, -1 if looser, 0 if same.
+func compareBound(b *float64, bExclusive bool, r *float64, rExclusive bool, upper bool) int {
+	switch {
+	case b == nil && r == nil:
+		return 0
+	case b == nil:
+		return 1
+	case r == nil:
+		return -1
+	}
+
+	bv, rv := *b, *r
+	if upper {
+		bv, rv = -bv, -rv
+	}
+	switch {
+	case rv > bv:
+		return 1
+	case rv < bv:
+		return -1
+	case rExclusive && !bExclusive:
+		return 1
+	case !rExclusive && bExclusive:
+		return -1
+	}
+	return 0
+}
+
+func (c *comparer) compareCount(ro operation, loc string, narrowed, widened Severity, minName, maxName string, bMin, rMin uint64, bMax, rMax *uint64) {
+	if rMin > bMin {
+		c.add(ro, narrowed, KindConstraintTightened, loc, "%s changed from %d to %d", minName, bMin, rMin)
+	} else if rMin < bMin {
+		c.add(ro, widened, KindConstraintRelaxed, loc, "%s changed from %d to %d", minName, bMin, rMin)
+	}
+
+	switch {
+	case bMax == nil && rMax == nil:
+	case bMax == nil:
+		c.add(ro, narrowed, KindConstraintTightened, loc, "%s %d added", maxName, *rMax)
+	case rMax == nil:
+		c.add(ro, widened, KindConstraintRelaxed, loc, "%s %d removed", maxName, *bMax)
+	case *rMax < *bMax:
+		c.add(ro, narrowed, KindConstraintTightened, loc, "%s changed from %d to %d", maxName, *bMax, *rMax)
+	case *rMax > *bMax:
+		c.add(ro, widened, KindConstraintRelaxed, loc, "%s changed from %d to %d", maxName, *bMax, *rMax)
+	}
+}
+
+// enumDiff returns values in a but not in b.
+func enumDiff(a, b []interface{}) []interface{} {
+	var d []interface{}
+out:
+	for _, av := range a {
+		for _, bv := range b {
+			if reflect.DeepEqual(av, bv) {
+				continue out
+			}
+		}
+		d = append(d, av)
+	}
+	return d
+}
+
+func formatBound(v *float64, exclusive bool) string {
+	if v == nil {
+		return "none"
+	}
+	if exclusive {
+		return fmt.Sprintf("%v (exclusive)", *v)
+	}
+	return fmt.Sprintf("%v", *v)
+}
+
+func formatValue(v interface{}) string {
+	if v == nil {
+		return "none"
+	}
+	switch t := v.(type) {
+	case string:
+		return fmt.Sprintf("%q", t)
+	case []interface{}:
+		s := "["
+		for i, e := range t {
+			if i > 0 {
+				s += ", "
+			}
+			s += formatValue(e)
+		}
+		return s + "]"
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func toSet(ss []string) map[string]bool {
+	m := make(map[string]bool, len(ss))
+	for _, s := range ss {
+		m[s] = true
+	}
+	return m
+}
+
+func sortedKeys(schemas openapi3.Schemas) []string {
+	keys := make([]string, 0, len(schemas))
+	for k := range schemas {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
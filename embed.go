@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+const embedTemplate = `// Code generated by goctl-openapi. DO NOT EDIT.
+
+package %s
+
+import _ "embed"
+
+// OpenAPI is the generated openapi document, it can be served by github.com/jayvynl/goctl-openapi/serve.
+//
+//go:embed %s
+var OpenAPI []byte
+`
+
+// writeEmbed writes go file name which embeds openapi file spec in the same directory,
+// package name is the same as other go files in the directory, or the directory name.
+func writeEmbed(name, spec string) error {
+	if filepath.Ext(name) != ".go" {
+		return fmt.Errorf("embed file \"%s\" must be a go file", name)
+	}
+	if filepath.Dir(name) != filepath.Dir(spec) {
+		return fmt.Errorf("embed file \"%s\" must be in the same directory as \"%s\"", name, spec)
+	}
+
+	content := fmt.Sprintf(embedTemplate, packageName(filepath.Dir(name), name), filepath.Base(spec))
+	return os.WriteFile(name, []byte(content), 0o644)
+}
+
+// packageName returns package name of go files in dir except exclude, or a valid name derived from dir name.
+func packageName(dir, exclude string) string {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, file := range files {
+		if file == exclude || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.PackageClauseOnly)
+		if err == nil {
+			return f.Name.Name
+		}
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "docs"
+	}
+	name := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, filepath.Base(abs))
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		return "docs"
+	}
+	return name
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPackageName(t *testing.T) {
+	tests := []struct {
+		name  string
+		dir   string
+		files map[string]string
+		want  string
+	}{
+		{"go files", "docs", map[string]string{"a.go": "package api\n"}, "api"},
+		{"test files", "docs", map[string]string{"a_test.go": "package api_test\n"}, "docs"},
+		{"excluded file", "docs", map[string]string{"openapi.go": "package api\n"}, "docs"},
+		{"invalid go file", "docs", map[string]string{"a.go": "invalid\n", "b.go": "package api\n"}, "api"},
+		{"dir name", "My-Docs.v2", nil, "mydocsv2"},
+		{"leading digit", "2docs", nil, "docs"},
+		{"no valid letter", "文档", nil, "docs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), tt.dir)
+			if err := os.Mkdir(dir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			writeFiles(t, dir, tt.files)
+			if got := packageName(dir, filepath.Join(dir, "openapi.go")); got != tt.want {
+				t.Errorf("packageName() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteEmbed(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "docs")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, dir, map[string]string{
+		"go.mod":       "module example.com/docs\n\ngo 1.20\n",
+		"docs.go":      "package api\n",
+		"openapi.json": `{"openapi": "3.0.3"}`,
+	})
+	name, spec := filepath.Join(dir, "openapi.go"), filepath.Join(dir, "openapi.json")
+
+	for _, bad := range []string{filepath.Join(dir, "openapi.txt"), filepath.Join(dir, "sub", "openapi.go")} {
+		if err := writeEmbed(bad, spec); err == nil {
+			t.Errorf("writeEmbed(%s) succeeded", bad)
+		}
+	}
+
+	if err := writeEmbed(name, spec); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"package api\n", "//go:embed openapi.json\nvar OpenAPI []byte\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("embed file doesn't contain %q:\n%s", want, data)
+		}
+	}
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	cmd := exec.Command(goBin, "vet", ".")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("embed file doesn't compile: %s\n%s", err, out)
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jayvynl/goctl-openapi/config"
+	"github.com/jayvynl/goctl-openapi/contract"
+	"github.com/jayvynl/goctl-openapi/httpfile"
+	"github.com/jayvynl/goctl-openapi/jsonschema"
+	"github.com/jayvynl/goctl-openapi/merge"
+	"github.com/jayvynl/goctl-openapi/oas3"
+	"github.com/jayvynl/goctl-openapi/overlay"
+	"github.com/jayvynl/goctl-openapi/rules"
+	"github.com/pkg/errors"
+	"github.com/zeromicro/go-zero/tools/goctl/api/parser"
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+	"github.com/zeromicro/go-zero/tools/goctl/plugin"
+)
+
+// postProcess merges hand-written fragments and applies overlays, relative paths are resolved against dir.
+// Conflicts and unmatched actions are reported to stderr, stdout may be the generated document.
+func postProcess(doc *openapi3.T, dir string, merges, overlays []string) error {
+	for _, m := range merges {
+		conflicts, err := merge.File(doc, config.ResolvePath(dir, m))
+		if err != nil {
+			return errors.WithMessagef(err, "merge %s", m)
+		}
+		for _, c := range conflicts {
+			fmt.Fprintf(os.Stderr, "goctl-openapi: merge %s: %s\n", m, c)
+		}
+	}
+
+	for _, o := range overlays {
+		ol, err := overlay.Load(config.ResolvePath(dir, o))
+		if err != nil {
+			return errors.WithMessagef(err, "overlay %s", o)
+		}
+		unmatched, err := ol.Apply(doc)
+		if err != nil {
+			return errors.WithMessagef(err, "overlay %s", o)
+		}
+		for _, u := range unmatched {
+			fmt.Fprintf(os.Stderr, "goctl-openapi: overlay %s: %s\n", o, u)
+		}
+	}
+	return nil
+}
+
+// writeHTTPFiles writes http request files of doc into dir.
+func writeHTTPFiles(doc *openapi3.T, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, f := range httpfile.Files(doc) {
+		if err := os.WriteFile(filepath.Join(dir, f.Name), f.Content, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeContractFiles writes contract tests of doc into dir, package name is the same as go files in dir.
+func writeContractFiles(doc *openapi3.T, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	files, err := contract.Files(doc, packageName(dir, ""))
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err = os.WriteFile(filepath.Join(dir, f.Name), f.Content, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// typeSchemas returns schemas of all struct types in api, diagnostics which are not reported by generation are printed.
+func typeSchemas(api *spec.ApiSpec, reported []oas3.Diagnostic) openapi3.Schemas {
+	schemas, diags := oas3.TypeSchemas(api)
+	seen := make(map[oas3.Diagnostic]bool, len(reported))
+	for _, d := range reported {
+		seen[d] = true
+	}
+	for _, d := range diags {
+		if !seen[d] {
+			fmt.Fprintf(os.Stderr, "goctl-openapi: %s\n", d)
+		}
+	}
+	return schemas
+}
+
+// writeJSONSchemaFiles writes json schema files of schemas into dir, one file per type.
+func writeJSONSchemaFiles(schemas openapi3.Schemas, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	files, err := jsonschema.Files(schemas)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err = os.WriteFile(filepath.Join(dir, f.Name), f.Content, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadRules adds transformation rules files to opts, relative paths are resolved against dir.
+func loadRules(opts *oas3.Options, dir string, files []string) error {
+	for _, f := range files {
+		rs, err := rules.Load(config.ResolvePath(dir, f))
+		if err != nil {
+			return errors.WithMessagef(err, "rules %s", f)
+		}
+		opts.Transformers = append(opts.Transformers, rs)
+	}
+	return nil
+}
+
+// loadDoc generates openapi document from api file with the config file next to it,
+// other files are loaded as openapi documents in json or yaml format.
+func loadDoc(filename string) (*openapi3.T, error) {
+	if !strings.HasSuffix(filename, ".api") {
+		doc, err := openapi3.NewLoader().LoadFromFile(filename)
+		if err != nil {
+			return nil, errors.WithMessage(err, filename)
+		}
+		return doc, nil
+	}
+
+	p, err := newPlugin(filename)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &config.Config{}
+	if cfgPath := config.Find(p.Dir); cfgPath != "" {
+		if cfg, err = config.Load(cfgPath); err != nil {
+			return nil, err
+		}
+	}
+	opts, err := cfg.Options()
+	if err != nil {
+		return nil, err
+	}
+	if err = loadRules(&opts, p.Dir, cfg.Rules); err != nil {
+		return nil, err
+	}
+	doc, diags, err := oas3.Generate(p.Api, opts)
+	if err != nil {
+		return nil, errors.WithMessage(err, filename)
+	}
+	// commands loading documents write results to stdout.
+	for _, d := range diags {
+		fmt.Fprintf(os.Stderr, "goctl-openapi: %s: %s\n", filename, d)
+	}
+	if err = postProcess(doc, p.Dir, cfg.Merge, cfg.Overlays); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// newPlugin parses api file without goctl, output directory is the directory of api file.
+func newPlugin(filename string) (*plugin.Plugin, error) {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+	api, err := parser.Parse(abs)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.Plugin{
+		Api:         api,
+		ApiFilePath: abs,
+		Dir:         filepath.Dir(abs),
+	}, nil
+}
@@ -4,9 +4,9 @@ go 1.20
 
 require (
 	github.com/getkin/kin-openapi v0.123.0
+	github.com/invopop/yaml v0.2.0
 	github.com/pkg/errors v0.9.1
 	github.com/zeromicro/go-zero/tools/goctl v1.6.3
-	gopkg.in/yaml.v2 v2.4.0
 )
 
 require (
@@ -15,7 +15,6 @@ require (
 	github.com/go-openapi/swag v0.22.8 // indirect
 	github.com/gookit/color v1.5.4 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/invopop/yaml v0.2.0 // indirect
 	github.com/josharian/intern v1.0.0 // indirect
 	github.com/mailru/easyjson v0.7.7 // indirect
 	github.com/mohae/deepcopy v0.0.0-20170929034955-c48cc78d4826 // indirect
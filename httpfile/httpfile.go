@@ -0,0 +1,251 @@
+package httpfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jayvynl/goctl-openapi/internal/docutil"
+	"github.com/jayvynl/goctl-openapi/sample"
+)
+
+// DefaultBaseURL is the value of baseUrl variable if the document has no server.
+const DefaultBaseURL = "http://localhost:8888"
+
+type (
+	// File is a http request file of JetBrains and VS Code REST Client.
+	File struct {
+		// Name is the file name, e.g. "book.http".
+		Name    string
+		Content []byte
+	}
+
+	file struct {
+		group    string
+		vars     []variable
+		varNames map[string]bool
+		requests strings.Builder
+	}
+
+	variable struct {
+		name  string
+		value string
+	}
+)
+
+// Files converts doc to http request files, one file per group, operations are grouped by the first tag,
+// which is the group name of routes by default. Parameters, optional or not, are file variables initialized with
+// sample values, operations requiring security use variable {{token}}.
+func Files(doc *openapi3.T) []File {
+	baseURL := DefaultBaseURL
+	if len(doc.Servers) > 0 {
+		baseURL = strings.TrimSuffix(doc.Servers[0].URL, "/")
+	}
+
+	files := make(map[string]*file)
+	var groups []string
+	paths := doc.Paths.InMatchingOrder()
+	sort.Strings(paths)
+	for _, path := range paths {
+		item := doc.Paths.Value(path)
+		for _, method := range docutil.Methods(item) {
+			op := item.GetOperation(method)
+			group := docutil.GroupTag(op.Tags)
+			f, ok := files[group]
+			if !ok {
+				f = &file{
+					group:    group,
+					vars:     []variable{{name: "baseUrl", value: baseURL}, {name: "token"}},
+					varNames: map[string]bool{"baseUrl": true, "token": true},
+				}
+				files[group] = f
+				groups = append(groups, group)
+			}
+			f.writeRequest(doc, path, method, item, op)
+		}
+	}
+
+	sort.Strings(groups)
+	result := make([]File, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, files[group].build())
+	}
+	return result
+}
+
+func (f *file) build() File {
+	var sb strings.Builder
+	for _, v := range f.vars {
+		fmt.Fprintf(&sb, "%s\n", strings.TrimSpace(fmt.Sprintf("@%s = %s", v.name, v.value)))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(f.requests.String())
+	return File{Name: docutil.FileName(f.group, docutil.DefaultTag) + ".http", Content: []byte(sb.String())}
+}
+
+// variable returns placeholder of parameter, the variable is declared with sample value at the first time.
+func (f *file) variable(name, value string) string {
+	name = variableName(name)
+	if !f.varNames[name] {
+		f.varNames[name] = true
+		f.vars = append(f.vars, variable{name: name, value: value})
+	}
+	return "{{" + name + "}}"
+}
+
+func (f *file) writeRequest(doc *openapi3.T, path, method string, pathItem *openapi3.PathItem, op *openapi3.Operation) {
+	sb := &f.requests
+	title := op.Summary
+	if title == "" {
+		title = fmt.Sprintf("%s %s", method, path)
+	}
+	fmt.Fprintf(sb, "### %s\n", oneLine(title))
+	if op.Description != "" {
+		fmt.Fprintf(sb, "# %s\n", oneLine(op.Description))
+	}
+	if op.OperationID != "" {
+		fmt.Fprintf(sb, "# @name %s\n", op.OperationID)
+	}
+
+	params := append(openapi3.Parameters{}, op.Parameters...)
+	for _, ref := range pathItem.Parameters {
+		if p := docutil.ResolveParameter(doc, ref); p != nil && op.Parameters.GetByInAndName(p.In, p.Name) == nil {
+			params = append(params, ref)
+		}
+	}
+	var (
+		query    []string
+		headers  []string
+		optional []string
+	)
+	for _, ref := range params {
+		p := docutil.ResolveParameter(doc, ref)
+		if p == nil {
+			continue
+		}
+		value := docutil.FormatValue(sample.Value(doc, p.Schema))
+		switch p.In {
+		case openapi3.ParameterInPath:
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", f.variable(p.Name, value))
+		case openapi3.ParameterInQuery:
+			query = append(query, url.QueryEscape(p.Name)+"="+f.variable(p.Name, value))
+		case openapi3.ParameterInHeader:
+			headers = append(headers, fmt.Sprintf("%s: %s", p.Name, f.variable(p.Name, value)))
+		}
+		if !p.Required && (p.In == openapi3.ParameterInQuery || p.In == openapi3.ParameterInHeader) {
+			optional = append(optional, fmt.Sprintf("%s (%s)", p.Name, p.In))
+		}
+	}
+	if len(optional) > 0 {
+		fmt.Fprintf(sb, "# optional parameters, remove if not wanted: %s\n", strings.Join(optional, ", "))
+	}
+
+	fmt.Fprintf(sb, "%s {{baseUrl}}%s", method, path)
+	if len(query) > 0 {
+		fmt.Fprintf(sb, "?%s", strings.Join(query, "&"))
+	}
+	sb.WriteString("\n")
+
+	if auth := authHeader(doc, op); auth != "" {
+		fmt.Fprintf(sb, "%s\n", auth)
+	}
+	for _, h := range headers {
+		fmt.Fprintf(sb, "%s\n", h)
+	}
+
+	var body string
+	if rb := docutil.ResolveRequestBody(doc, op.RequestBody); rb != nil {
+		var contentType string
+		contentType, body = newBody(doc, rb.Content)
+		if contentType != "" {
+			fmt.Fprintf(sb, "Content-Type: %s\n", contentType)
+		}
+	}
+	if body != "" {
+		fmt.Fprintf(sb, "\n%s\n", body)
+	}
+	sb.WriteString("\n")
+}
+
+// newBody synthesizes request body of json or urlencoded form.
+func newBody(doc *openapi3.T, content openapi3.Content) (string, string) {
+	if mt := content.Get("application/json"); mt != nil {
+		data, err := json.MarshalIndent(sample.Value(doc, mt.Schema), "", "  ")
+		if err != nil {
+			return "", ""
+		}
+		return "application/json", string(data)
+	}
+	if mt := content.Get("application/x-www-form-urlencoded"); mt != nil {
+		obj, _ := sample.Value(doc, mt.Schema).(map[string]interface{})
+		s := docutil.ResolveSchema(doc, mt.Schema)
+		if s == nil {
+			return "", ""
+		}
+		// only required fields are filled, like required query parameters.
+		required := append([]string{}, s.Required...)
+		sort.Strings(required)
+		values := make([]string, 0, len(required))
+		for _, name := range required {
+			values = append(values, url.QueryEscape(name)+"="+url.QueryEscape(docutil.FormatValue(obj[name])))
+		}
+		return "application/x-www-form-urlencoded", strings.Join(values, "&")
+	}
+	return "", ""
+}
+
+// authHeader returns authorization header of the first supported security requirement of operation, routes of jwt
+// groups have their own requirements, the global requirement of the document is ignored like postman collections.
+func authHeader(doc *openapi3.T, op *openapi3.Operation) string {
+	if op.Security == nil || doc.Components == nil {
+		return ""
+	}
+	for _, requirement := range *op.Security {
+		if header := requirementHeader(doc, requirement); header != "" {
+			return header
+		}
+	}
+	return ""
+}
+
+// requirementHeader returns authorization header of the first supported security scheme of requirement in name order.
+func requirementHeader(doc *openapi3.T, requirement openapi3.SecurityRequirement) string {
+	names := make([]string, 0, len(requirement))
+	for name := range requirement {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ref, ok := doc.Components.SecuritySchemes[name]
+		if !ok || ref.Value == nil {
+			continue
+		}
+		scheme := ref.Value
+		switch {
+		case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "bearer"):
+			return "Authorization: Bearer {{token}}"
+		case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+			return "Authorization: Basic {{token}}"
+		case scheme.Type == "apiKey" && scheme.In == openapi3.ParameterInHeader:
+			return fmt.Sprintf("%s: {{token}}", scheme.Name)
+		}
+	}
+	return ""
+}
+
+// variableName replaces characters which are not allowed in variable names.
+func variableName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,143 @@
+package httpfile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const spec = `
+openapi: 3.0.3
+info: {title: books, version: v1}
+servers: [{url: "https://example.com/v1/"}]
+security: [{jwt: []}]
+paths:
+  /books/{id}:
+    get:
+      tags: [book]
+      summary: Get book
+      description: |
+        Get a book
+        by id.
+      operationId: getBook
+      security: [{jwt: []}]
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer, example: 7}}
+        - {name: page, in: query, schema: {type: integer, example: 2}}
+        - {name: X-Trace-Id, in: header, schema: {type: string, example: abc}}
+        - {name: lang, in: query, required: true, schema: {type: string, enum: [en]}}
+      responses: {"200": {description: ok}}
+    put:
+      tags: [book]
+      security: [{oauth: []}, {apiKey: []}]
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+      requestBody:
+        content:
+          application/json:
+            schema: {type: object, properties: {name: {type: string, example: go}}}
+      responses: {"200": {description: ok}}
+  /login:
+    post:
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              required: [user, password]
+              properties: {user: {type: string, example: bob}, password: {type: string, example: "a b"}, remember: {type: boolean}}
+      responses: {"200": {description: ok}}
+  /admin/stats:
+    get:
+      tags: [admin/v1]
+      security: []
+      responses: {"200": {description: ok}}
+components:
+  securitySchemes:
+    jwt: {type: http, scheme: bearer}
+    apiKey: {type: apiKey, in: header, name: X-Api-Key}
+    oauth: {type: oauth2, flows: {implicit: {authorizationUrl: "https://example.com/auth", scopes: {}}}}
+`
+
+func TestFiles(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	var names []string
+	for _, f := range Files(doc) {
+		files[f.Name] = string(f.Content)
+		names = append(names, f.Name)
+	}
+	if got := strings.Join(names, ","); got != "admin_v1.http,book.http,default.http" {
+		t.Fatalf("files = %s, want admin_v1.http,book.http,default.http", got)
+	}
+
+	tests := []struct {
+		name string
+		file string
+		want []string
+		not  []string
+	}{
+		{
+			name: "variables",
+			file: "book.http",
+			want: []string{"@baseUrl = https://example.com/v1\n@token =\n@id = 7\n@page = 2\n@X_Trace_Id = abc\n@lang = en\n"},
+		},
+		{
+			name: "request",
+			file: "book.http",
+			want: []string{
+				"### Get book\n# Get a book by id.\n# @name getBook\n",
+				"# optional parameters, remove if not wanted: page (query), X-Trace-Id (header)\n",
+				"GET {{baseUrl}}/books/{{id}}?page={{page}}&lang={{lang}}\nAuthorization: Bearer {{token}}\nX-Trace-Id: {{X_Trace_Id}}\n",
+			},
+		},
+		{
+			name: "json body and api key",
+			file: "book.http",
+			want: []string{"### PUT /books/{id}\nPUT {{baseUrl}}/books/{{id}}\nX-Api-Key: {{token}}\nContent-Type: application/json\n\n{\n  \"name\": \"go\"\n}\n"},
+		},
+		{
+			name: "form body without global security",
+			file: "default.http",
+			want: []string{"POST {{baseUrl}}/login\nContent-Type: application/x-www-form-urlencoded\n\npassword=a+b&user=bob\n"},
+			not:  []string{"Authorization", "remember"},
+		},
+		{
+			name: "unsecured",
+			file: "admin_v1.http",
+			want: []string{"GET {{baseUrl}}/admin/stats\n\n"},
+			not:  []string{"Authorization"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := files[tt.file]
+			for _, want := range tt.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("%s doesn't contain %q:\n%s", tt.file, want, content)
+				}
+			}
+			for _, not := range tt.not {
+				if strings.Contains(content, not) {
+					t.Errorf("%s contains %q:\n%s", tt.file, not, content)
+				}
+			}
+		})
+	}
+}
+
+func TestVariableName(t *testing.T) {
+	for name, want := range map[string]string{
+		"id":         "id",
+		"X-Trace-Id": "X_Trace_Id",
+		"a.b[0]":     "a_b_0_",
+	} {
+		if got := variableName(name); got != want {
+			t.Errorf("variableName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,545 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jayvynl/goctl-openapi/constant"
+	"github.com/jayvynl/goctl-openapi/internal/docutil"
+	"github.com/jayvynl/goctl-openapi/oas3"
+	"github.com/pkg/errors"
+	"github.com/zeromicro/go-zero/tools/goctl/pkg/parser/api/format"
+	"github.com/zeromicro/go-zero/tools/goctl/pkg/parser/api/parser"
+)
+
+// Options customize the generated api file.
+type Options struct {
+	// Service is the service name, default derived from info title.
+	Service string
+}
+
+type (
+	typeDef struct {
+		name   string
+		docs   []string
+		fields []*field
+	}
+
+	field struct {
+		name    string // empty for embedded struct
+		typ     string
+		tag     string
+		key     string // tag key, e.g. json, form
+		wire    string // field name in tag
+		docs    []string
+		comment string
+	}
+
+	route struct {
+		docs     [][2]string // @doc properties
+		handler  string
+		method   string
+		path     string
+		request  string
+		response string
+	}
+
+	group struct {
+		name    string
+		secured bool
+		routes  []*route
+	}
+
+	converter struct {
+		doc      *openapi3.T
+		types    []*typeDef
+		names    map[string]bool   // used type names
+		structs  map[string]string // component schema name -> type name
+		handlers map[string]bool   // used handler names
+		warnings []string
+	}
+)
+
+var (
+	methods = []string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodOptions, http.MethodTrace, http.MethodConnect,
+	}
+	pathParamRe  = regexp.MustCompile(`{([^/{}]+)}`)
+	validPathRe  = regexp.MustCompile(`^(/[A-Za-z0-9_\-.:]*)+$`)
+	identifierRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Import converts openapi document to api file source,
+// features which can't be represented in api file are skipped and reported as warnings.
+func Import(doc *openapi3.T, opts Options) ([]byte, []string, error) {
+	c := &converter{
+		doc:      doc,
+		names:    make(map[string]bool),
+		structs:  make(map[string]string),
+		handlers: make(map[string]bool),
+	}
+	c.defineComponents()
+	groups := c.groups()
+
+	service := opts.Service
+	if service == "" && doc.Info != nil {
+		service = oas3.ConvertName("pascal", doc.Info.Title)
+	}
+	if !identifierRe.MatchString(service) {
+		service = "Service"
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("syntax = \"v1\"\n\n")
+	c.writeInfo(&buf)
+	for _, td := range c.types {
+		writeType(&buf, td)
+	}
+	for _, g := range groups {
+		writeGroup(&buf, service, g)
+	}
+
+	var out bytes.Buffer
+	if err := format.Source(buf.Bytes(), &out); err != nil {
+		return nil, c.warnings, errors.WithMessage(err, "format api file")
+	}
+	if _, err := parser.Parse("", out.Bytes()); err != nil {
+		return nil, c.warnings, errors.WithMessage(err, "parse api file")
+	}
+	return out.Bytes(), c.warnings, nil
+}
+
+func (c *converter) warnf(format string, args ...interface{}) {
+	c.warnings = append(c.warnings, fmt.Sprintf(format, args...))
+}
+
+// groups converts operations to routes, grouped by the first tag and whether jwt is required.
+func (c *converter) groups() []*group {
+	var (
+		groups []*group
+		index  = make(map[string]*group)
+	)
+	if c.doc.Paths == nil {
+		return nil
+	}
+
+	paths := make([]string, 0, c.doc.Paths.Len())
+	for p := range c.doc.Paths.Map() {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		item := c.doc.Paths.Value(p)
+		for _, method := range methods {
+			op := item.GetOperation(method)
+			if op == nil {
+				continue
+			}
+			if !validPathRe.MatchString(pathParamRe.ReplaceAllString(p, ":$1")) {
+				c.warnf("%s %s: path is not supported by api file", method, p)
+				continue
+			}
+			r := c.route(method, p, item, op)
+
+			var tag string
+			if len(op.Tags) > 0 {
+				tag = op.Tags[0]
+			}
+			name := oas3.ConvertName("camel", tag)
+			secured := c.secured(op)
+			key := fmt.Sprintf("%s/%t", name, secured)
+			g, ok := index[key]
+			if !ok {
+				g = &group{name: name, secured: secured}
+				index[key] = g
+				groups = append(groups, g)
+			}
+			if len(op.Tags) > 1 || (tag != "" && tag != name) {
+				r.docs = append(r.docs, [2]string{constant.ApiInfoTags, strings.Join(op.Tags, ",")})
+			}
+			g.routes = append(g.routes, r)
+		}
+	}
+	return groups
+}
+
+func (c *converter) route(method, p string, item *openapi3.PathItem, op *openapi3.Operation) *route {
+	r := &route{
+		handler: c.handlerName(method, p, op.OperationID),
+		method:  strings.ToLower(method),
+		path:    pathParamRe.ReplaceAllString(p, ":$1"),
+	}
+	if summary := strings.TrimSpace(op.Summary); summary != "" {
+		r.docs = append(r.docs, [2]string{"summary", summary})
+	}
+	if desc := strings.TrimSpace(op.Description); desc != "" {
+		r.docs = append(r.docs, [2]string{"description", desc})
+	}
+	if op.ExternalDocs != nil && op.ExternalDocs.URL != "" {
+		r.docs = append(r.docs, [2]string{constant.ApiInfoExternalDocs, op.ExternalDocs.URL})
+	}
+	if op.Servers != nil && len(*op.Servers) > 0 {
+		urls := make([]string, len(*op.Servers))
+		for i, s := range *op.Servers {
+			urls[i] = s.URL
+		}
+		r.docs = append(r.docs, [2]string{constant.ApiInfoServers, strings.Join(urls, ",")})
+	}
+	if v, ok := op.Extensions[constant.ExtensionVisibility].(string); ok && v != "" {
+		r.docs = append(r.docs, [2]string{constant.ApiInfoVisibility, v})
+	}
+
+	loc := fmt.Sprintf("%s %s", method, p)
+	r.request = c.request(loc, r.handler, item, op)
+	r.response = c.response(loc, r.handler, op)
+	return r
+}
+
+func (c *converter) handlerName(method, p, operationID string) string {
+	name := oas3.ConvertName("pascal", operationID)
+	if !identifierRe.MatchString(name) {
+		name = oas3.ConvertName("pascal", method+" "+pathParamRe.ReplaceAllString(p, "$1"))
+	}
+	if !identifierRe.MatchString(name) {
+		name = "Handler"
+	}
+	handler := name
+	for i := 2; c.handlers[handler]; i++ {
+		handler = fmt.Sprintf("%s%d", name, i)
+	}
+	c.handlers[handler] = true
+	return handler
+}
+
+// secured reports whether operation requires any security scheme.
+func (c *converter) secured(op *openapi3.Operation) bool {
+	reqs := c.doc.Security
+	if op.Security != nil {
+		reqs = *op.Security
+	}
+	if len(reqs) == 0 {
+		return false
+	}
+	for _, req := range reqs {
+		// empty requirement makes security optional
+		if len(req) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// request defines request type from parameters and request body, returns empty string if there is neither.
+func (c *converter) request(loc, handler string, item *openapi3.PathItem, op *openapi3.Operation) string {
+	td := &typeDef{}
+
+	var params openapi3.Parameters
+	params = append(params, item.Parameters...)
+	for _, ref := range op.Parameters {
+		p := docutil.ResolveParameter(c.doc, ref)
+		if p == nil {
+			continue
+		}
+		for i, pr := range params {
+			if q := docutil.ResolveParameter(c.doc, pr); q != nil && q.Name == p.Name && q.In == p.In {
+				params = append(params[:i], params[i+1:]...)
+				break
+			}
+		}
+		params = append(params, ref)
+	}
+
+	for _, ref := range params {
+		p := docutil.ResolveParameter(c.doc, ref)
+		if p == nil {
+			c.warnf("%s: unresolved parameter %s", loc, ref.Ref)
+			continue
+		}
+		var key string
+		switch p.In {
+		case openapi3.ParameterInQuery:
+			key = constant.TagKeyForm
+		case openapi3.ParameterInPath:
+			key = constant.TagKeyPath
+		case openapi3.ParameterInHeader:
+			key = constant.TagKeyHeader
+		default:
+			c.warnf("%s: %s parameter \"%s\" is not supported", loc, p.In, p.Name)
+			continue
+		}
+		schema := p.Schema
+		if schema == nil {
+			schema = openapi3.NewStringSchema().NewRef()
+		}
+		f := c.field(td, handler+"Request", key, p.Name, schema, p.Required || p.In == openapi3.ParameterInPath)
+		if p.Description != "" {
+			f.comment = oneLine(p.Description)
+		}
+		if p.Deprecated {
+			f.docs = []string{"Deprecated:"}
+		}
+	}
+
+	body := docutil.ResolveRequestBody(c.doc, op.RequestBody)
+	var embedded string
+	name := handler + "Request"
+	if body != nil {
+		mt, key := requestMediaType(body.Content)
+		switch {
+		case mt == nil:
+			if len(body.Content) > 0 {
+				c.warnf("%s: request body media types are not supported", loc)
+			}
+		case mt.Schema == nil:
+		case key == constant.TagKeyJson && c.bodyStruct(mt.Schema) != "":
+			embedded = c.bodyStruct(mt.Schema)
+		case c.isStruct(docutil.ResolveSchema(c.doc, mt.Schema)):
+			schema := docutil.ResolveSchema(c.doc, mt.Schema)
+			// title of body schema generated by oas3 is the request type name
+			if identifierRe.MatchString(schema.Title) {
+				name = schema.Title
+			}
+			td.docs = docLines(body.Description)
+			c.structFields(td, name, key, schema)
+		default:
+			c.warnf("%s: request body is not an object", loc)
+		}
+	}
+
+	if embedded != "" {
+		if len(td.fields) == 0 {
+			return embedded
+		}
+		td.fields = append(td.fields, &field{typ: embedded})
+	}
+	if len(td.fields) == 0 {
+		return ""
+	}
+	td.name = c.newName(name)
+	c.types = append(c.types, td)
+	return td.name
+}
+
+// bodyStruct returns type name if json request body is a struct component,
+// an inline schema with the same title and properties of a struct component is considered the same.
+func (c *converter) bodyStruct(ref *openapi3.SchemaRef) string {
+	if name := c.structName(ref); name != "" || ref.Value == nil {
+		return name
+	}
+	s := ref.Value
+	name, ok := c.structs[s.Title]
+	if !ok {
+		return ""
+	}
+	cs := c.doc.Components.Schemas[s.Title].Value
+	if len(s.AllOf) > 0 || len(cs.AllOf) > 0 || len(s.Properties) != len(cs.Properties) ||
+		!sameStrings(s.Required, cs.Required) {
+		return ""
+	}
+	for pn := range s.Properties {
+		if _, ok := cs.Properties[pn]; !ok {
+			return ""
+		}
+	}
+	return name
+}
+
+// requestMediaType returns json media type, or form media type, and the corresponding tag key.
+func requestMediaType(content openapi3.Content) (*openapi3.MediaType, string) {
+	keys := make([]string, 0, len(content))
+	for k := range content {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if strings.Contains(k, "json") {
+			return content[k], constant.TagKeyJson
+		}
+	}
+	for _, k := range keys {
+		if k == "application/x-www-form-urlencoded" || k == "multipart/form-data" {
+			return content[k], constant.TagKeyForm
+		}
+	}
+	return nil, ""
+}
+
+// response returns the type of success json response, empty string if not representable.
+func (c *converter) response(loc, handler string, op *openapi3.Operation) string {
+	if op.Responses == nil {
+		return ""
+	}
+	var codes []string
+	for code := range op.Responses.Map() {
+		if len(code) == 3 && code[0] == '2' {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return ""
+	}
+	sort.Strings(codes)
+
+	resp := docutil.ResolveResponse(c.doc, op.Responses.Value(codes[0]))
+	if resp == nil {
+		return ""
+	}
+	var schema *openapi3.SchemaRef
+	for mt, media := range resp.Content {
+		if strings.Contains(mt, "json") && media.Schema != nil {
+			schema = media.Schema
+			break
+		}
+	}
+	if schema == nil {
+		return ""
+	}
+
+	typ := c.typeExpr(schema, handler+"Response")
+	name := strings.TrimLeft(typ, "[]*")
+	if !c.names[name] {
+		c.warnf("%s: response of type \"%s\" is not supported", loc, typ)
+		return ""
+	}
+	return typ
+}
+
+func (c *converter) writeInfo(buf *bytes.Buffer) {
+	info := c.doc.Info
+	if info == nil {
+		return
+	}
+	var props [][2]string
+	add := func(key, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			props = append(props, [2]string{key, value})
+		}
+	}
+	add(constant.ApiInfoTitle, info.Title)
+	add(constant.ApiInfoDesc, info.Description)
+	if info.Contact != nil {
+		add(constant.ApiInfoAuthor, info.Contact.Name)
+		add(constant.ApiInfoEmail, info.Contact.Email)
+	}
+	if date, ok := info.Extensions[constant.ExtensionDate].(string); ok {
+		add(constant.ApiInfoDate, date)
+	}
+	add(constant.ApiInfoVersion, info.Version)
+	urls := make([]string, 0, len(c.doc.Servers))
+	for _, s := range c.doc.Servers {
+		urls = append(urls, s.URL)
+	}
+	add(constant.ApiInfoServers, strings.Join(urls, ","))
+	if c.doc.ExternalDocs != nil {
+		add(constant.ApiInfoExternalDocs, c.doc.ExternalDocs.URL)
+	}
+	tags := make([]string, 0, len(c.doc.Tags))
+	for _, t := range c.doc.Tags {
+		tags = append(tags, t.Name)
+		if t.Description != "" || t.ExternalDocs != nil {
+			c.warnf("tag %s: description and externalDocs are not supported", t.Name)
+		}
+	}
+	add(constant.ApiInfoTags, strings.Join(tags, ","))
+
+	if info.TermsOfService != "" {
+		c.warnf("info: termsOfService is not supported")
+	}
+	if info.License != nil {
+		c.warnf("info: license is not supported")
+	}
+	if info.Contact != nil && info.Contact.URL != "" {
+		c.warnf("info: contact url is not supported")
+	}
+	if len(props) == 0 {
+		return
+	}
+
+	buf.WriteString("info (\n")
+	for _, p := range props {
+		fmt.Fprintf(buf, "\t%s: %s\n", p[0], strconv.Quote(p[1]))
+	}
+	buf.WriteString(")\n\n")
+}
+
+func writeType(buf *bytes.Buffer, td *typeDef) {
+	for _, d := range td.docs {
+		fmt.Fprintf(buf, "// %s\n", d)
+	}
+	fmt.Fprintf(buf, "type %s {\n", td.name)
+	for _, f := range td.fields {
+		for _, d := range f.docs {
+			fmt.Fprintf(buf, "\t// %s\n", d)
+		}
+		if f.name == "" {
+			fmt.Fprintf(buf, "\t%s\n", f.typ)
+			continue
+		}
+		fmt.Fprintf(buf, "\t%s %s `%s`", f.name, f.typ, f.tag)
+		if f.comment != "" {
+			fmt.Fprintf(buf, " // %s", f.comment)
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n\n")
+}
+
+func writeGroup(buf *bytes.Buffer, service string, g *group) {
+	if g.name != "" || g.secured {
+		buf.WriteString("@server (\n")
+		if g.secured {
+			buf.WriteString("\tjwt: Auth\n")
+		}
+		if g.name != "" {
+			fmt.Fprintf(buf, "\tgroup: %s\n", g.name)
+		}
+		buf.WriteString(")\n")
+	}
+	fmt.Fprintf(buf, "service %s {\n", service)
+	for i, r := range g.routes {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		if len(r.docs) > 0 {
+			buf.WriteString("\t@doc (\n")
+			for _, d := range r.docs {
+				fmt.Fprintf(buf, "\t\t%s: %s\n", d[0], strconv.Quote(d[1]))
+			}
+			buf.WriteString("\t)\n")
+		}
+		fmt.Fprintf(buf, "\t@handler %s\n", r.handler)
+		fmt.Fprintf(buf, "\t%s %s", r.method, r.path)
+		if r.request != "" {
+			fmt.Fprintf(buf, " (%s)", r.request)
+		}
+		if r.response != "" {
+			fmt.Fprintf(buf, " returns (%s)", r.response)
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n\n")
+}
+
+// docLines splits description into comment lines.
+func docLines(desc string) []string {
+	var lines []string
+	for _, line := range strings.Split(desc, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// oneLine joins description into a line comment, comment marker kept by goctl is removed.
+func oneLine(desc string) string {
+	return strings.Join(strings.Fields(strings.TrimPrefix(strings.TrimSpace(desc), "//")), " ")
+}
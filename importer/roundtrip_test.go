@@ -0,0 +1,216 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jayvynl/goctl-openapi/internal/golden"
+	"github.com/jayvynl/goctl-openapi/oas3"
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+	"github.com/zeromicro/go-zero/tools/goctl/pkg/parser/api/parser"
+)
+
+// roundTripFiles are api files of the corpus, golden api files are stored next to them with ".golden" suffix,
+// golden files of api files out of testdata are stored in testdata/roundtrip.
+func roundTripFiles(t *testing.T) []string {
+	files, err := filepath.Glob(filepath.Join("testdata", "roundtrip", "*.api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append([]string{filepath.Join("..", "example", "example.api")}, files...)
+}
+
+// Known limitations of the round trip, the normalized documents and routes are still equal:
+//   - order of struct fields, properties of schemas are unordered, the imported fields are sorted by name,
+//     required properties are compared as sets.
+//   - service name, the document doesn't have it, imported services are named after the info title,
+//     "Service" if the title is not an identifier, e.g. "service Example" becomes "service Service".
+//   - group names of routes with @doc tags, groups of imported routes are named after their first tag.
+//   - key types of maps, keys of schema maps are strings, map[int]T becomes map[string]T.
+//   - validate rules of map keys between "keys" and "endkeys", schemas can't constrain keys.
+//   - validate "required" of parameters, it only clears allowEmptyValue of parameters, which is the default
+//     of openapi, so imported parameters don't have it.
+//   - routes without jwt under global security, they inherit the requirement of the document and
+//     are imported with jwt, the corpus is generated without global security.
+func TestRoundTrip(t *testing.T) {
+	for _, filename := range roundTripFiles(t) {
+		name := strings.TrimSuffix(filepath.Base(filename), ".api")
+		t.Run(name, func(t *testing.T) {
+			src, err := os.ReadFile(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			base := generate(t, filename, src)
+
+			imported, warnings, err := Import(base, Options{})
+			if err != nil {
+				t.Fatalf("import: %s", err)
+			}
+			for _, w := range warnings {
+				t.Errorf("import warning: %s", w)
+			}
+			golden.Check(t, filepath.Join("testdata", "roundtrip", name+".api.golden"), imported)
+
+			revision := generate(t, name+".imported.api", imported)
+			compareValues(t, "", normalize(t, base), normalize(t, revision))
+			compareRoutes(t, parse(t, filename, src), parse(t, name+".imported.api", imported))
+
+			// converting again is stable.
+			again, _, err := Import(revision, Options{})
+			if err != nil {
+				t.Fatalf("import again: %s", err)
+			}
+			if !bytes.Equal(imported, again) {
+				t.Errorf("importing round-tripped document again differs:\n%s", again)
+			}
+		})
+	}
+}
+
+func parse(t *testing.T, filename string, src []byte) *spec.ApiSpec {
+	t.Helper()
+	api, err := parser.Parse(filename, src)
+	if err != nil {
+		t.Fatalf("parse %s: %s", filename, err)
+	}
+	return api
+}
+
+func generate(t *testing.T, filename string, src []byte) *openapi3.T {
+	t.Helper()
+	opts := oas3.DefaultOptions()
+	opts.Security.Global = false
+	doc, diags, err := oas3.Generate(parse(t, filename, src), opts)
+	if err != nil {
+		t.Fatalf("generate %s: %s", filename, err)
+	}
+	for _, d := range diags {
+		t.Errorf("diagnostic of %s: %s", filename, d)
+	}
+	return doc
+}
+
+// normalize converts document to json values, with required properties sorted and allowEmptyValue of parameters removed.
+func normalize(t *testing.T, doc *openapi3.T) interface{} {
+	t.Helper()
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v interface{}
+	if err = json.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if _, ok := v["in"].(string); ok {
+				delete(v, "allowEmptyValue")
+			}
+			if required, ok := v["required"].([]interface{}); ok {
+				sort.Slice(required, func(i, j int) bool {
+					return fmt.Sprint(required[i]) < fmt.Sprint(required[j])
+				})
+			}
+			for _, e := range v {
+				walk(e)
+			}
+		case []interface{}:
+			for _, e := range v {
+				walk(e)
+			}
+		}
+	}
+	walk(v)
+	return v
+}
+
+// compareValues reports differences between json values of base and revision documents.
+func compareValues(t *testing.T, path string, base, revision interface{}) {
+	t.Helper()
+	switch b := base.(type) {
+	case map[string]interface{}:
+		r, ok := revision.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make(map[string]bool)
+		for k := range b {
+			keys[k] = true
+		}
+		for k := range r {
+			keys[k] = true
+		}
+		for k := range keys {
+			compareValues(t, path+"/"+k, b[k], r[k])
+		}
+		return
+	case []interface{}:
+		r, ok := revision.([]interface{})
+		if !ok || len(b) != len(r) {
+			break
+		}
+		for i := range b {
+			compareValues(t, fmt.Sprintf("%s/%d", path, i), b[i], r[i])
+		}
+		return
+	}
+	if !reflect.DeepEqual(base, revision) {
+		t.Errorf("round trip changed %s from %s to %s", path, marshal(base), marshal(revision))
+	}
+}
+
+func marshal(v interface{}) string {
+	if v == nil {
+		return "nothing"
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// compareRoutes checks routes have the same handlers and jwt annotations, routes are identified by method and path.
+func compareRoutes(t *testing.T, base, revision *spec.ApiSpec) {
+	t.Helper()
+	type route struct {
+		handler string
+		jwt     string
+	}
+	routes := func(api *spec.ApiSpec) map[string]route {
+		m := make(map[string]route)
+		for _, g := range api.Service.Groups {
+			prefix := g.GetAnnotation(spec.RoutePrefixKey)
+			for _, r := range g.Routes {
+				m[r.Method+" "+path.Join("/", prefix, r.Path)] = route{handler: r.Handler, jwt: g.GetAnnotation("jwt")}
+			}
+		}
+		return m
+	}
+
+	baseRoutes, revRoutes := routes(base), routes(revision)
+	for key, br := range baseRoutes {
+		rr, ok := revRoutes[key]
+		if !ok {
+			t.Errorf("round trip removed route %s", key)
+			continue
+		}
+		if br != rr {
+			t.Errorf("round trip changed route %s from %+v to %+v", key, br, rr)
+		}
+	}
+	for key := range revRoutes {
+		if _, ok := baseRoutes[key]; !ok {
+			t.Errorf("round trip added route %s", key)
+		}
+	}
+}
@@ -0,0 +1,445 @@
+package importer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jayvynl/goctl-openapi/constant"
+	"github.com/jayvynl/goctl-openapi/internal/docutil"
+	"github.com/jayvynl/goctl-openapi/oas3"
+)
+
+// defineComponents defines types of object schemas in components, other schemas are inlined where referenced.
+func (c *converter) defineComponents() {
+	if c.doc.Components == nil {
+		c.doc.Components = &openapi3.Components{}
+		return
+	}
+
+	names := make([]string, 0, len(c.doc.Components.Schemas))
+	for name, ref := range c.doc.Components.Schemas {
+		if ref != nil && ref.Value != nil && c.isStruct(ref.Value) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	// register names first, for breaking cycle type reference.
+	for _, name := range names {
+		c.structs[name] = c.newName(name)
+	}
+	for _, name := range names {
+		c.defineStruct(c.structs[name], c.doc.Components.Schemas[name].Value)
+	}
+}
+
+func (c *converter) defineStruct(name string, s *openapi3.Schema) {
+	td := &typeDef{
+		name: name,
+		docs: docLines(s.Description),
+	}
+	if s.Deprecated {
+		td.docs = append(td.docs, "Deprecated:")
+	}
+	c.types = append(c.types, td)
+	c.structFields(td, name, constant.TagKeyJson, s)
+}
+
+// structFields adds properties of object schema as fields, allOf references of struct are embedded.
+func (c *converter) structFields(td *typeDef, hint, key string, s *openapi3.Schema) {
+	for _, ref := range s.AllOf {
+		if name := c.structName(ref); name != "" {
+			td.fields = append(td.fields, &field{typ: name})
+		} else if sub := docutil.ResolveSchema(c.doc, ref); sub != nil {
+			c.structFields(td, hint, key, sub)
+		}
+	}
+
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ref := s.Properties[name]
+		f := c.field(td, hint, key, name, ref, contains(s.Required, name))
+		// description of referenced struct belongs to the struct
+		if ref.Ref == "" && ref.Value != nil {
+			f.comment = oneLine(ref.Value.Description)
+			if ref.Value.Deprecated {
+				f.docs = []string{"Deprecated:"}
+			}
+		}
+	}
+}
+
+// field adds a field to td, a field with the same tag key and name is replaced.
+func (c *converter) field(td *typeDef, hint, key, name string, ref *openapi3.SchemaRef, required bool) *field {
+	var f *field
+	for _, of := range td.fields {
+		if of.key == key && of.wire == name {
+			f = of
+			break
+		}
+	}
+	if f == nil {
+		f = &field{name: c.fieldName(td, name), key: key, wire: name}
+		td.fields = append(td.fields, f)
+	}
+	f.typ = c.typeExpr(ref, hint+f.name)
+	f.tag = c.tag(key, name, docutil.ResolveSchema(c.doc, ref), required)
+	return f
+}
+
+func (c *converter) fieldName(td *typeDef, name string) string {
+	base := oas3.ConvertName("pascal", name)
+	if !identifierRe.MatchString(base) {
+		base = "Field" + base
+		if !identifierRe.MatchString(base) {
+			base = "Field"
+		}
+	}
+	fn := base
+	for i := 2; ; i++ {
+		exists := false
+		for _, f := range td.fields {
+			if f.name == fn {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			return fn
+		}
+		fn = fmt.Sprintf("%s%d", base, i)
+	}
+}
+
+// newName returns an unused type name derived from name.
+func (c *converter) newName(name string) string {
+	base := oas3.ConvertName("pascal", name)
+	if !identifierRe.MatchString(base) {
+		base = "Type" + base
+		if !identifierRe.MatchString(base) {
+			base = "Type"
+		}
+	}
+	typ := base
+	for i := 2; c.names[typ]; i++ {
+		typ = fmt.Sprintf("%s%d", base, i)
+	}
+	c.names[typ] = true
+	return typ
+}
+
+// structName returns type name if ref is a reference to struct component.
+func (c *converter) structName(ref *openapi3.SchemaRef) string {
+	if ref == nil || ref.Ref == "" {
+		return ""
+	}
+	return c.structs[docutil.RefName(ref.Ref)]
+}
+
+// isStruct reports whether schema is an object with properties, or composed of structs by allOf.
+func (c *converter) isStruct(s *openapi3.Schema) bool {
+	if s == nil {
+		return false
+	}
+	if len(s.Properties) > 0 {
+		return true
+	}
+	if len(s.AllOf) == 0 {
+		return false
+	}
+	for _, ref := range s.AllOf {
+		if c.structName(ref) == "" && !c.isStruct(docutil.ResolveSchema(c.doc, ref)) {
+			return false
+		}
+	}
+	return true
+}
+
+// typeExpr returns go type of schema, inline objects are defined as new types named after hint.
+func (c *converter) typeExpr(ref *openapi3.SchemaRef, hint string) string {
+	if name := c.structName(ref); name != "" {
+		return name
+	}
+	s := docutil.ResolveSchema(c.doc, ref)
+	if s == nil {
+		return "interface{}"
+	}
+	if len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		c.warnf("%s: oneOf and anyOf are converted to interface{}", hint)
+		return "interface{}"
+	}
+
+	var ptr string
+	if s.Nullable {
+		ptr = "*"
+	}
+	// nullable reference {"nullable": true, "allOf": [{"$ref": "..."}]}
+	if len(s.AllOf) == 1 && len(s.Properties) == 0 {
+		if name := c.structName(s.AllOf[0]); name != "" {
+			return ptr + name
+		}
+	}
+	if c.isStruct(s) {
+		name := c.newName(hint)
+		c.defineStruct(name, s)
+		return ptr + name
+	}
+
+	switch s.Type {
+	case openapi3.TypeString:
+		if s.Format == constant.FormatBinary {
+			return "interface{}"
+		}
+		return ptr + "string"
+	case openapi3.TypeInteger:
+		switch s.Format {
+		case constant.FormatInt, constant.FormatInt8, constant.FormatInt16, constant.FormatInt32, constant.FormatInt64,
+			constant.FormatUint, constant.FormatUint8, constant.FormatUint16, constant.FormatUint32, constant.FormatUint64:
+			return ptr + s.Format
+		}
+		return ptr + "int64"
+	case openapi3.TypeNumber:
+		if s.Format == constant.FormatFloat {
+			return ptr + "float32"
+		}
+		return ptr + "float64"
+	case openapi3.TypeBoolean:
+		return ptr + "bool"
+	case openapi3.TypeArray:
+		item := c.typeExpr(s.Items, hint+"Item")
+		if fixedArray(s) {
+			return fmt.Sprintf("[%d]%s", s.MinItems, item)
+		}
+		return "[]" + item
+	case openapi3.TypeObject:
+		if s.AdditionalProperties.Schema != nil {
+			return "map[string]" + c.typeExpr(s.AdditionalProperties.Schema, hint+"Value")
+		}
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+// fixedArray reports whether schema is an array with fixed length, slices are nullable.
+func fixedArray(s *openapi3.Schema) bool {
+	return !s.Nullable && s.MinItems > 0 && s.MaxItems != nil && *s.MaxItems == s.MinItems
+}
+
+// tag returns struct tag of field, constraints of json and form fields are expressed by
+// go-zero options if possible, others are expressed by validate tag.
+func (c *converter) tag(key, name string, s *openapi3.Schema, required bool) string {
+	opts := []string{name}
+	apiOptions := s != nil && (key == constant.TagKeyJson || key == constant.TagKeyForm) && primitive(s)
+	var (
+		defaultValue                  string
+		enumInOptions, rangeInOptions bool
+	)
+	if apiOptions {
+		if d, ok := formatValue(s.Default); ok && !strings.ContainsAny(d, `,"`) {
+			defaultValue = d
+		}
+	}
+	// field with default value is optional
+	if defaultValue != "" {
+		opts = append(opts, fmt.Sprintf("%s=%s", constant.OptionDefault, defaultValue))
+	} else if !required && key != constant.TagKeyPath {
+		opts = append(opts, constant.OptionOptional)
+	}
+	if apiOptions {
+		if values, ok := formatValues(s.Enum, ",|\"` \t\n"); ok {
+			opts = append(opts, fmt.Sprintf("%s=%s", constant.OptionOptions, strings.Join(values, "|")))
+			enumInOptions = true
+		}
+		if (s.Type == openapi3.TypeInteger || s.Type == openapi3.TypeNumber) && (s.Min != nil || s.Max != nil) {
+			opts = append(opts, fmt.Sprintf("%s=%s", constant.OptionRange, formatRange(s)))
+			rangeInOptions = true
+		}
+	}
+
+	tag := fmt.Sprintf(`%s:"%s"`, key, strings.Join(opts, ","))
+	if s == nil {
+		return tag
+	}
+	rules := c.validateRules(s, enumInOptions, rangeInOptions)
+	if len(rules) == 0 {
+		return tag
+	}
+	if !required {
+		rules = append([]string{constant.OptionOmitempty}, rules...)
+	}
+	return fmt.Sprintf(`%s %s:"%s"`, tag, constant.TagKeyValidate, strings.Join(rules, ","))
+}
+
+// validateRules converts constraints to validator rules, nested item constraints follow "dive".
+func (c *converter) validateRules(s *openapi3.Schema, skipEnum, skipRange bool) []string {
+	var rules []string
+	switch s.Type {
+	case openapi3.TypeString:
+		rules = lengthRules(s.MinLength, s.MaxLength)
+	case openapi3.TypeInteger, openapi3.TypeNumber:
+		if !skipRange {
+			rules = rangeRules(s)
+		}
+	case openapi3.TypeArray:
+		if !fixedArray(s) {
+			rules = lengthRules(s.MinItems, s.MaxItems)
+		}
+		if item := docutil.ResolveSchema(c.doc, s.Items); item != nil && c.structName(s.Items) == "" {
+			if sub := c.validateRules(item, false, false); len(sub) > 0 {
+				rules = append(append(rules, "dive"), sub...)
+			}
+		}
+	case openapi3.TypeObject:
+		rules = lengthRules(s.MinProps, s.MaxProps)
+		if ap := s.AdditionalProperties.Schema; ap != nil && c.structName(ap) == "" {
+			if sub := c.validateRules(docutil.ResolveSchema(c.doc, ap), false, false); len(sub) > 0 {
+				rules = append(append(rules, "dive"), sub...)
+			}
+		}
+	}
+
+	if !skipEnum && len(s.Enum) > 0 && primitive(s) {
+		// https://pkg.go.dev/github.com/go-playground/validator/v10#hdr-One_Of
+		if values, ok := formatValues(s.Enum, "'\"`\t\n"); ok {
+			for i, v := range values {
+				v = strings.ReplaceAll(strings.ReplaceAll(v, ",", "0x2C"), "|", "0x7C")
+				if strings.Contains(v, " ") {
+					v = "'" + v + "'"
+				}
+				values[i] = v
+			}
+			rules = append(rules, "oneof="+strings.Join(values, " "))
+		} else {
+			c.warnf("enum %v is not supported by validate tag", s.Enum)
+		}
+	}
+	return rules
+}
+
+func lengthRules(min uint64, max *uint64) []string {
+	if max != nil && *max == min {
+		return []string{fmt.Sprintf("len=%d", min)}
+	}
+	var rules []string
+	if min > 0 {
+		rules = append(rules, fmt.Sprintf("min=%d", min))
+	}
+	if max != nil {
+		rules = append(rules, fmt.Sprintf("max=%d", *max))
+	}
+	return rules
+}
+
+func rangeRules(s *openapi3.Schema) []string {
+	var rules []string
+	if s.Min != nil {
+		key := "min"
+		if s.ExclusiveMin {
+			key = "gt"
+		}
+		rules = append(rules, fmt.Sprintf("%s=%s", key, formatNumber(*s.Min)))
+	}
+	if s.Max != nil {
+		key := "max"
+		if s.ExclusiveMax {
+			key = "lt"
+		}
+		rules = append(rules, fmt.Sprintf("%s=%s", key, formatNumber(*s.Max)))
+	}
+	return rules
+}
+
+// formatRange returns go-zero range option value, e.g. [1:10), (:5].
+func formatRange(s *openapi3.Schema) string {
+	var b strings.Builder
+	if s.ExclusiveMin {
+		b.WriteByte('(')
+	} else {
+		b.WriteByte('[')
+	}
+	if s.Min != nil {
+		b.WriteString(formatNumber(*s.Min))
+	}
+	b.WriteByte(':')
+	if s.Max != nil {
+		b.WriteString(formatNumber(*s.Max))
+	}
+	if s.ExclusiveMax {
+		b.WriteByte(')')
+	} else {
+		b.WriteByte(']')
+	}
+	return b.String()
+}
+
+func primitive(s *openapi3.Schema) bool {
+	switch s.Type {
+	case openapi3.TypeString, openapi3.TypeInteger, openapi3.TypeNumber, openapi3.TypeBoolean:
+		return s.Format != constant.FormatBinary
+	}
+	return false
+}
+
+// formatValues formats enum values, ok is false if enum is empty or any value is empty or contains chars.
+func formatValues(enum []interface{}, chars string) ([]string, bool) {
+	if len(enum) == 0 {
+		return nil, false
+	}
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		v, ok := formatValue(e)
+		if !ok || v == "" || strings.ContainsAny(v, chars) {
+			return nil, false
+		}
+		values[i] = v
+	}
+	return values, true
+}
+
+func formatValue(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case float64:
+		return formatNumber(v), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	}
+	return "", false
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// sameStrings reports whether a and b contain the same strings regardless of order.
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, s := range a {
+		if !contains(b, s) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,178 @@
+// Package docutil provides helpers shared by generators reading openapi documents.
+package docutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// DefaultTag groups operations without tags.
+const DefaultTag = "default"
+
+// GroupTag returns the tag which groups an operation, operations with several tags are grouped by the first one only.
+func GroupTag(tags []string) string {
+	if len(tags) == 0 {
+		return DefaultTag
+	}
+	return tags[0]
+}
+
+// RefName returns the last segment of a component reference.
+func RefName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// ResolveSchema returns value of schema reference, looking up components of doc if the value is not loaded.
+func ResolveSchema(doc *openapi3.T, ref *openapi3.SchemaRef) *openapi3.Schema {
+	if ref == nil {
+		return nil
+	}
+	if ref.Value != nil {
+		return ref.Value
+	}
+	if doc.Components == nil {
+		return nil
+	}
+	if s, ok := doc.Components.Schemas[RefName(ref.Ref)]; ok && s != ref {
+		return ResolveSchema(doc, s)
+	}
+	return nil
+}
+
+// ResolveParameter returns value of parameter reference, looking up components of doc if the value is not loaded.
+func ResolveParameter(doc *openapi3.T, ref *openapi3.ParameterRef) *openapi3.Parameter {
+	if ref == nil {
+		return nil
+	}
+	if ref.Value != nil {
+		return ref.Value
+	}
+	if doc.Components == nil {
+		return nil
+	}
+	if p, ok := doc.Components.Parameters[RefName(ref.Ref)]; ok && p != ref {
+		return ResolveParameter(doc, p)
+	}
+	return nil
+}
+
+// ResolveRequestBody returns value of request body reference, looking up components of doc if the value is not loaded.
+func ResolveRequestBody(doc *openapi3.T, ref *openapi3.RequestBodyRef) *openapi3.RequestBody {
+	if ref == nil {
+		return nil
+	}
+	if ref.Value != nil {
+		return ref.Value
+	}
+	if doc.Components == nil {
+		return nil
+	}
+	if b, ok := doc.Components.RequestBodies[RefName(ref.Ref)]; ok && b != ref {
+		return ResolveRequestBody(doc, b)
+	}
+	return nil
+}
+
+// ResolveResponse returns value of response reference, looking up components of doc if the value is not loaded.
+func ResolveResponse(doc *openapi3.T, ref *openapi3.ResponseRef) *openapi3.Response {
+	if ref == nil {
+		return nil
+	}
+	if ref.Value != nil {
+		return ref.Value
+	}
+	if doc.Components == nil {
+		return nil
+	}
+	if r, ok := doc.Components.Responses[RefName(ref.Ref)]; ok && r != ref {
+		return ResolveResponse(doc, r)
+	}
+	return nil
+}
+
+// Methods returns http methods of path item in conventional order.
+func Methods(item *openapi3.PathItem) []string {
+	var ms []string
+	for _, method := range []string{
+		http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodHead, http.MethodOptions, http.MethodTrace,
+	} {
+		if item.GetOperation(method) != nil {
+			ms = append(ms, method)
+		}
+	}
+	return ms
+}
+
+// FileName replaces characters which are not allowed or special in file names, fallback is used for empty name.
+func FileName(name, fallback string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', ' ', '.':
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
+// FormatValue formats sample value as parameter or form value, arrays are comma separated.
+func FormatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, e := range v {
+			values = append(values, FormatValue(e))
+		}
+		return strings.Join(values, ",")
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// FirstMediaType returns json media type, or the first media type in name order.
+func FirstMediaType(content openapi3.Content) *openapi3.MediaType {
+	if mt := content.Get("application/json"); mt != nil {
+		return mt
+	}
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	if len(types) == 0 {
+		return nil
+	}
+	sort.Strings(types)
+	return content[types[0]]
+}
+
+// SuccessResponse returns the 2xx response with the lowest status, or the default response with status 200.
+func SuccessResponse(responses *openapi3.Responses) (int, *openapi3.ResponseRef) {
+	best := 0
+	for code := range responses.Map() {
+		status, err := strconv.Atoi(code)
+		if err == nil && status >= 200 && status < 300 && (best == 0 || status < best) {
+			best = status
+		}
+	}
+	if best != 0 {
+		return best, responses.Status(best)
+	}
+	return http.StatusOK, responses.Default()
+}
@@ -0,0 +1,183 @@
+package docutil
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const spec = `
+openapi: 3.0.3
+info: {title: test, version: v1}
+paths:
+  /books:
+    delete: {responses: {"204": {description: deleted}}}
+    post: {responses: {"201": {description: created}, "200": {$ref: "#/components/responses/Book"}}}
+    get:
+      parameters: [{$ref: "#/components/parameters/Page"}]
+      requestBody: {$ref: "#/components/requestBodies/Filter"}
+      responses: {default: {description: default}}
+components:
+  schemas:
+    Book: {$ref: "#/components/schemas/Novel"}
+    Novel: {type: object}
+  parameters:
+    Page: {name: page, in: query, schema: {type: integer}}
+  requestBodies:
+    Filter: {content: {application/json: {schema: {$ref: "#/components/schemas/Book"}}}}
+  responses:
+    Book: {description: book}
+`
+
+func load(t *testing.T) *openapi3.T {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestResolve(t *testing.T) {
+	doc := load(t)
+	op := doc.Paths.Value("/books").Get
+
+	// loaded references have values, unloaded references are looked up in components.
+	tests := []struct {
+		name string
+		got  bool
+	}{
+		{"loaded schema", ResolveSchema(doc, doc.Components.Schemas["Book"]) == doc.Components.Schemas["Novel"].Value},
+		{"schema ref", ResolveSchema(doc, openapi3.NewSchemaRef("#/components/schemas/Book", nil)) == doc.Components.Schemas["Novel"].Value},
+		{"missing schema", ResolveSchema(doc, openapi3.NewSchemaRef("#/components/schemas/Author", nil)) == nil},
+		{"nil schema", ResolveSchema(doc, nil) == nil},
+		{"self reference", ResolveSchema(&openapi3.T{Components: &openapi3.Components{Schemas: openapi3.Schemas{"A": {Ref: "#/components/schemas/A"}}}},
+			&openapi3.SchemaRef{Ref: "#/components/schemas/A"}) == nil},
+		{"no components", ResolveSchema(&openapi3.T{}, &openapi3.SchemaRef{Ref: "#/components/schemas/A"}) == nil},
+		{"parameter", ResolveParameter(doc, op.Parameters[0]).Name == "page"},
+		{"parameter ref", ResolveParameter(doc, &openapi3.ParameterRef{Ref: "#/components/parameters/Page"}).Name == "page"},
+		{"request body", ResolveRequestBody(doc, &openapi3.RequestBodyRef{Ref: "#/components/requestBodies/Filter"}) == op.RequestBody.Value},
+		{"response", ResolveResponse(doc, &openapi3.ResponseRef{Ref: "#/components/responses/Book"}).Description != nil},
+		{"missing response", ResolveResponse(doc, &openapi3.ResponseRef{Ref: "#/components/responses/Author"}) == nil},
+	}
+	for _, tt := range tests {
+		if !tt.got {
+			t.Errorf("%s is not resolved", tt.name)
+		}
+	}
+}
+
+func TestGroupTag(t *testing.T) {
+	tests := []struct {
+		tags []string
+		want string
+	}{
+		{nil, DefaultTag},
+		{[]string{}, DefaultTag},
+		{[]string{"book"}, "book"},
+		{[]string{"book", "author"}, "book"},
+	}
+	for _, tt := range tests {
+		if got := GroupTag(tt.tags); got != tt.want {
+			t.Errorf("GroupTag(%v) = %s, want %s", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestRefName(t *testing.T) {
+	for ref, want := range map[string]string{
+		"#/components/schemas/Book": "Book",
+		"Book":                      "Book",
+		"":                          "",
+	} {
+		if got := RefName(ref); got != want {
+			t.Errorf("RefName(%q) = %q, want %q", ref, got, want)
+		}
+	}
+}
+
+func TestMethods(t *testing.T) {
+	doc := load(t)
+	if got := strings.Join(Methods(doc.Paths.Value("/books")), ","); got != "GET,POST,DELETE" {
+		t.Errorf("Methods() = %s, want GET,POST,DELETE", got)
+	}
+}
+
+func TestFileName(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"book", "book"},
+		{"book/v1", "book_v1"},
+		{"../book", "___book"},
+		{`a:b*c?"d"<e>|f g\h`, "a_b_c__d__e__f_g_h"},
+		{"", "default"},
+	}
+	for _, tt := range tests {
+		if got := FileName(tt.name, "default"); got != tt.want {
+			t.Errorf("FileName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{nil, ""},
+		{"a b", "a b"},
+		{float64(1.5), "1.5"},
+		{true, "true"},
+		{[]interface{}{"a", float64(1), []interface{}{"b"}}, "a,1,b"},
+		{map[string]interface{}{"a": "b"}, `{"a":"b"}`},
+	}
+	for _, tt := range tests {
+		if got := FormatValue(tt.value); got != tt.want {
+			t.Errorf("FormatValue(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestFirstMediaType(t *testing.T) {
+	json := openapi3.NewMediaType()
+	form := openapi3.NewMediaType()
+	xml := openapi3.NewMediaType()
+	tests := []struct {
+		name    string
+		content openapi3.Content
+		want    *openapi3.MediaType
+	}{
+		{"json", openapi3.Content{"text/xml": xml, "application/json": json}, json},
+		{"name order", openapi3.Content{"text/xml": xml, "application/x-www-form-urlencoded": form}, form},
+		{"empty", nil, nil},
+	}
+	for _, tt := range tests {
+		if got := FirstMediaType(tt.content); got != tt.want {
+			t.Errorf("%s: FirstMediaType() = %p, want %p", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSuccessResponse(t *testing.T) {
+	doc := load(t)
+	tests := []struct {
+		method string
+		want   string
+	}{
+		{"DELETE", "204 deleted"},
+		{"POST", "200 book"},
+		{"GET", "200 default"},
+	}
+	for _, tt := range tests {
+		status, ref := SuccessResponse(doc.Paths.Value("/books").GetOperation(tt.method).Responses)
+		if got := fmt.Sprintf("%d %s", status, *ResolveResponse(doc, ref).Description); got != tt.want {
+			t.Errorf("SuccessResponse() of %s = %s, want %s", tt.method, got, tt.want)
+		}
+	}
+	if _, ref := SuccessResponse(nil); ref != nil {
+		t.Errorf("SuccessResponse(nil) = %v, want nil", ref)
+	}
+}
@@ -0,0 +1,29 @@
+// Package golden compares test outputs with golden files.
+package golden
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// Check compares got with content of golden file, the file is overwritten if tests run with -update.
+func Check(t *testing.T, golden string, got []byte) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("%s, run with -update to create golden files", err)
+	}
+	if !bytes.Equal(want, got) {
+		t.Errorf("%s mismatch, run with -update to update golden files\ngot:\n%s", golden, got)
+	}
+}
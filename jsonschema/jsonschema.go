@@ -0,0 +1,149 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jayvynl/goctl-openapi/internal/docutil"
+)
+
+// Dialect is the json schema dialect of generated documents.
+const Dialect = "https://json-schema.org/draft/2020-12/schema"
+
+// File is a json schema document of a type.
+type File struct {
+	// Name is the file name, e.g. "Book.json".
+	Name    string
+	Content []byte
+}
+
+// Bundle converts openapi schemas to a json schema document with schemas in $defs,
+// references are "#/$defs/<name>".
+func Bundle(title string, schemas openapi3.Schemas) ([]byte, error) {
+	defs := make(map[string]interface{}, len(schemas))
+	for name, s := range schemas {
+		v, err := convert(s, func(name string) string { return "#/$defs/" + name })
+		if err != nil {
+			return nil, err
+		}
+		defs[name] = v
+	}
+	doc := map[string]interface{}{
+		"$schema": Dialect,
+		"$defs":   defs,
+	}
+	if title != "" {
+		doc["title"] = title
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// Files converts openapi schemas to json schema documents, one file per schema,
+// references are relative file names "<name>.json".
+func Files(schemas openapi3.Schemas) ([]File, error) {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	files := make([]File, 0, len(names))
+	for _, name := range names {
+		v, err := convert(schemas[name], func(name string) string { return name + ".json" })
+		if err != nil {
+			return nil, err
+		}
+		v["$schema"] = Dialect
+		v["$id"] = name + ".json"
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Name: name + ".json", Content: append(data, '\n')})
+	}
+	return files, nil
+}
+
+// convert converts openapi 3.0 schema to json schema 2020-12, ref maps component names to references.
+func convert(s *openapi3.SchemaRef, ref func(name string) string) (map[string]interface{}, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	var v map[string]interface{}
+	if err = json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return convertSchema(v, ref), nil
+}
+
+func convertSchema(s map[string]interface{}, ref func(name string) string) map[string]interface{} {
+	if r, ok := s["$ref"].(string); ok {
+		s["$ref"] = ref(docutil.RefName(r))
+		return s
+	}
+
+	if props, ok := s["properties"].(map[string]interface{}); ok {
+		for name, p := range props {
+			if m, ok := p.(map[string]interface{}); ok {
+				props[name] = convertSchema(m, ref)
+			}
+		}
+	}
+	for _, key := range []string{"items", "additionalProperties", "not"} {
+		if m, ok := s[key].(map[string]interface{}); ok {
+			s[key] = convertSchema(m, ref)
+		}
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		if list, ok := s[key].([]interface{}); ok {
+			for i, e := range list {
+				if m, ok := e.(map[string]interface{}); ok {
+					list[i] = convertSchema(m, ref)
+				}
+			}
+		}
+	}
+
+	// exclusive bounds are numbers instead of flags of minimum and maximum.
+	for _, bound := range []string{"Minimum", "Maximum"} {
+		key := "exclusive" + bound
+		if exclusive, _ := s[key].(bool); exclusive {
+			s[key] = s[strings.ToLower(bound)]
+			delete(s, strings.ToLower(bound))
+		} else {
+			delete(s, key)
+		}
+	}
+	if example, ok := s["example"]; ok {
+		s["examples"] = []interface{}{example}
+		delete(s, "example")
+	}
+
+	if nullable, _ := s["nullable"].(bool); nullable {
+		delete(s, "nullable")
+		if enum, ok := s["enum"].([]interface{}); ok {
+			s["enum"] = append(enum, nil)
+		}
+		switch {
+		case s["type"] != nil:
+			s["type"] = []interface{}{s["type"], "null"}
+		case isList(s["allOf"], 1):
+			// nullable reference.
+			s["anyOf"] = []interface{}{s["allOf"].([]interface{})[0], map[string]interface{}{"type": "null"}}
+			delete(s, "allOf")
+		default:
+			s = map[string]interface{}{"anyOf": []interface{}{s, map[string]interface{}{"type": "null"}}}
+		}
+	}
+	delete(s, "discriminator")
+	delete(s, "xml")
+	return s
+}
+
+func isList(v interface{}, n int) bool {
+	list, ok := v.([]interface{})
+	return ok && len(list) == n
+}
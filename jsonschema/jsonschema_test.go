@@ -0,0 +1,135 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{
+			name:   "reference",
+			schema: `{"$ref": "#/components/schemas/Author"}`,
+			want:   `{"$ref":"Author.json"}`,
+		},
+		{
+			name:   "nested references",
+			schema: `{"type": "object", "properties": {"a": {"$ref": "#/components/schemas/A"}, "b": {"type": "array", "items": {"$ref": "#/components/schemas/B"}}}, "additionalProperties": {"oneOf": [{"$ref": "#/components/schemas/C"}]}}`,
+			want:   `{"additionalProperties":{"oneOf":[{"$ref":"C.json"}]},"properties":{"a":{"$ref":"A.json"},"b":{"items":{"$ref":"B.json"},"type":"array"}},"type":"object"}`,
+		},
+		{
+			name:   "exclusive bounds",
+			schema: `{"type": "integer", "minimum": 1, "exclusiveMinimum": true, "maximum": 9}`,
+			want:   `{"exclusiveMinimum":1,"maximum":9,"type":"integer"}`,
+		},
+		{
+			name:   "example",
+			schema: `{"type": "string", "example": "go"}`,
+			want:   `{"examples":["go"],"type":"string"}`,
+		},
+		{
+			name:   "nullable type",
+			schema: `{"type": "string", "nullable": true, "enum": ["a"]}`,
+			want:   `{"enum":["a",null],"type":["string","null"]}`,
+		},
+		{
+			name:   "nullable reference",
+			schema: `{"nullable": true, "allOf": [{"$ref": "#/components/schemas/Author"}]}`,
+			want:   `{"anyOf":[{"$ref":"Author.json"},{"type":"null"}]}`,
+		},
+		{
+			name:   "nullable composition",
+			schema: `{"nullable": true, "oneOf": [{"type": "string"}, {"type": "integer"}]}`,
+			want:   `{"anyOf":[{"oneOf":[{"type":"string"},{"type":"integer"}]},{"type":"null"}]}`,
+		},
+		{
+			name:   "openapi only keywords",
+			schema: `{"type": "object", "discriminator": {"propertyName": "kind"}, "xml": {"name": "a"}}`,
+			want:   `{"type":"object"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref := &openapi3.SchemaRef{}
+			if err := json.Unmarshal([]byte(tt.schema), ref); err != nil {
+				t.Fatal(err)
+			}
+			v, err := convert(ref, func(name string) string { return name + ".json" })
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, _ := json.Marshal(v)
+			if string(data) != tt.want {
+				t.Errorf("convert() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func schemas(t *testing.T) openapi3.Schemas {
+	t.Helper()
+	var s openapi3.Schemas
+	data := `{
+		"Book": {"type": "object", "properties": {"author": {"$ref": "#/components/schemas/Author"}}},
+		"Author": {"type": "object", "properties": {"name": {"type": "string"}}}
+	}`
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestBundle(t *testing.T) {
+	data, err := Bundle("books", schemas(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v map[string]interface{}
+	if err = json.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"dialect", v["$schema"], Dialect},
+		{"title", v["title"], "books"},
+		{"reference", v["$defs"].(map[string]interface{})["Book"].(map[string]interface{})["properties"].(map[string]interface{})["author"].(map[string]interface{})["$ref"], "#/$defs/Author"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	if data, err = Bundle("", nil); err != nil || strings.Contains(string(data), "title") {
+		t.Errorf("Bundle() without title = %s, %v", data, err)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	files, err := Files(schemas(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].Name != "Author.json" || files[1].Name != "Book.json" {
+		t.Fatalf("files = %v, want Author.json and Book.json", files)
+	}
+	var v map[string]interface{}
+	if err = json.Unmarshal(files[1].Content, &v); err != nil {
+		t.Fatal(err)
+	}
+	if v["$id"] != "Book.json" || v["$schema"] != Dialect {
+		t.Errorf("Book.json = %s", files[1].Content)
+	}
+	if !strings.Contains(string(files[1].Content), `"$ref": "Author.json"`) {
+		t.Errorf("Book.json doesn't reference Author.json: %s", files[1].Content)
+	}
+}
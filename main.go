@@ -1,41 +1,190 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
 	"runtime"
 	"strings"
 
+	"github.com/invopop/yaml"
+	"github.com/jayvynl/goctl-openapi/config"
+	"github.com/jayvynl/goctl-openapi/jsonschema"
 	"github.com/jayvynl/goctl-openapi/oas3"
+	"github.com/jayvynl/goctl-openapi/postman"
+	"github.com/jayvynl/goctl-openapi/render"
+	"github.com/jayvynl/goctl-openapi/typescript"
 	"github.com/zeromicro/go-zero/tools/goctl/plugin"
-	"gopkg.in/yaml.v2"
 )
 
 const Version = "v1.6.0"
 
 var (
-	version = flag.Bool("version", false, `show version and exit.`)
-	output  = flag.String("filename", "", `openapi file name, default "openapi.json", "-" will output to stdout.`)
-	format  = flag.String("format", "", `serialization format, "json" or "yaml", default "json".`)
-	pretty  = flag.Bool("pretty", false, `pretty print of json.`)
+	version      = flag.Bool("version", false, `show version and exit.`)
+	output       = flag.String("filename", "", `openapi file name, default "openapi.json", "-" will output to stdout.`)
+	format       = flag.String("format", "", `serialization format, "json", "yaml", "html", "markdown", "postman", "jsonschema" or "typescript", default "json".`)
+	contractDir  = flag.String("contract", "", `directory of go-zero handlers, generate contract tests of handlers into it.`)
+	httpDir      = flag.String("http", "", `directory of http request files, generate one ".http" file per group into it.`)
+	schemaDir    = flag.String("jsonschema", "", `directory of json schema files, generate one json schema 2020-12 file per type into it.`)
+	tsFile       = flag.String("typescript", "", `typescript file name, generate typescript definitions of types and operations into it, e.g. "api.d.ts".`)
+	headingLevel = flag.Int("heading-level", 1, `heading level of document title in markdown format, from 1 to 4.`)
+	pretty       = flag.Bool("pretty", false, `pretty print of json.`)
+	allTypes     = flag.Bool("all-types", false, `emit schemas of all types defined in api file, even if they are not used by any route.`)
+	examples     = flag.Bool("examples", false, `synthesize examples of schemas, parameters, request bodies and responses.`)
+	exampleDepth = flag.Int("example-depth", 0, `how many times a recursive schema is expanded in examples, default 1.`)
+	apiFile      = flag.String("api", "", `api file, generate without goctl instead of reading plugin context from stdin.`)
+	outDir       = flag.String("dir", "", `output directory when generating without goctl, default the directory of api file.`)
+	watch        = flag.Bool("watch", false, `regenerate when the api file or files it imports change, requires -api.`)
+	cfgFile      = flag.String("config", "", `config file, default ".goctl-openapi.yaml" next to the api file, relative to the current directory.`)
+	embed        = flag.String("embed", "", `go file name, generate the go file embedding openapi file next to it, e.g. "openapi.go".`)
+	merges       stringSlice
+	overlays     stringSlice
+	ruleFiles    stringSlice
+	includes     stringSlice
+	excludes     stringSlice
 )
 
+func init() {
+	flag.Var(&merges, "merge", `partial openapi file (json or yaml) deep-merged into the generated document, can be repeated, later files take precedence.`)
+	flag.Var(&overlays, "overlay", `openapi overlay file (json or yaml) applied to the generated document, can be repeated, applied in order after merging.`)
+	flag.Var(&ruleFiles, "rules", `transformation rules file (json or yaml) customizing generated operations, can be repeated, applied in order.`)
+	flag.Var(&includes, "include", `only include routes matching "kind:pattern", kind is one of group, tag, path, handler and visibility, can be repeated.`)
+	flag.Var(&excludes, "exclude", `exclude routes matching "kind:pattern", kind is one of group, tag, path, handler and visibility, can be repeated.`)
+}
+
+// extensions are file name extensions of output formats.
+var extensions = map[string]string{
+	"json":       "json",
+	"yaml":       "yaml",
+	"html":       "html",
+	"markdown":   "md",
+	"postman":    "postman_collection.json",
+	"jsonschema": "schema.json",
+	"typescript": "d.ts",
+}
+
+// stringSlice is a flag.Value which can be set multiple times.
+type stringSlice []string
+
+func (s *stringSlice) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSlice) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage of goctl-openapi:\n")
+	fmt.Fprintf(out, "  goctl-openapi [flags]                          goctl api plugin, reads plugin context from stdin\n")
+	fmt.Fprintf(out, "  goctl-openapi -api file [flags]                generate from api file without goctl, -watch regenerates on changes\n")
+	fmt.Fprintf(out, "  goctl-openapi diff [flags] base revision       report changes between two api or openapi files\n")
+	fmt.Fprintf(out, "  goctl-openapi changelog [flags] base revision  generate markdown changelog between two api or openapi files\n")
+	fmt.Fprintf(out, "  goctl-openapi import [flags] file              convert openapi file to api file\n")
+	fmt.Fprintf(out, "  goctl-openapi mock [flags] file                serve mock responses of api or openapi file\n")
+	fmt.Fprintf(out, "Flags:\n")
+	flag.PrintDefaults()
+}
+
 func main() {
+	flag.Usage = usage
 	flag.Parse()
 	if *version {
 		fmt.Printf("goctl-openapi %s %s/%s\n", Version, runtime.GOOS, runtime.GOARCH)
 		return
 	}
 
-	p, err := plugin.NewPlugin()
+	if flag.NArg() > 0 {
+		switch flag.Arg(0) {
+		case "diff":
+			runDiff(flag.Args()[1:])
+		case "changelog":
+			runChangelog(flag.Args()[1:])
+		case "import":
+			runImport(flag.Args()[1:])
+		case "mock":
+			runMock(flag.Args()[1:])
+		default:
+			fmt.Printf("goctl-openapi: unknown command \"%s\"\n", flag.Arg(0))
+			os.Exit(2)
+		}
+		return
+	}
+
+	var (
+		p   *plugin.Plugin
+		err error
+	)
+	if *apiFile == "" {
+		if *watch {
+			fmt.Println("goctl-openapi: watch requires an api file passed by -api")
+			return
+		}
+		p, err = plugin.NewPlugin()
+	} else {
+		// the api file is parsed by every generation in standalone mode.
+		var abs string
+		abs, err = filepath.Abs(*apiFile)
+		p = &plugin.Plugin{ApiFilePath: abs, Dir: filepath.Dir(abs)}
+		if *outDir != "" {
+			p.Dir = *outDir
+		}
+	}
 	if err != nil {
 		fmt.Printf("goctl-openapi: %s\n", err)
 		return
 	}
 
+	cfg := &config.Config{}
+	cfgPath := config.Find(path.Dir(p.ApiFilePath))
+	if *cfgFile != "" {
+		// relative to the current directory like -api, paths in the config file are relative to the config file.
+		if cfgPath, err = filepath.Abs(*cfgFile); err != nil {
+			fmt.Printf("goctl-openapi: %s\n", err)
+			return
+		}
+	}
+	if cfgPath != "" {
+		cfg, err = config.Load(cfgPath)
+		if err != nil {
+			fmt.Printf("goctl-openapi: %s\n", err)
+			return
+		}
+	}
+	applyConfig(cfg)
+	opts, err := cfg.Options()
+	if err != nil {
+		fmt.Printf("goctl-openapi: %s\n", err)
+		return
+	}
+	opts.AllTypes = opts.AllTypes || *allTypes
+	if *exampleDepth < 0 {
+		fmt.Printf("goctl-openapi: invalid example depth %d\n", *exampleDepth)
+		return
+	}
+	for _, expr := range includes {
+		if err = opts.Filter.Include.Set(expr); err != nil {
+			fmt.Printf("goctl-openapi: %s\n", err)
+			return
+		}
+	}
+	for _, expr := range excludes {
+		if err = opts.Filter.Exclude.Set(expr); err != nil {
+			fmt.Printf("goctl-openapi: %s\n", err)
+			return
+		}
+	}
+	if err = loadRules(&opts, p.Dir, ruleFiles); err != nil {
+		fmt.Printf("goctl-openapi: %s\n", err)
+		return
+	}
+
 	var (
 		o = "openapi"
 		f = "json"
@@ -43,10 +192,20 @@ func main() {
 	if *output != "" {
 		o = *output
 	}
-	if strings.HasSuffix(o, ".json") {
+	if strings.HasSuffix(o, ".postman_collection.json") {
+		f = "postman"
+	} else if strings.HasSuffix(o, ".schema.json") {
+		f = "jsonschema"
+	} else if strings.HasSuffix(o, ".ts") {
+		f = "typescript"
+	} else if strings.HasSuffix(o, ".json") {
 		f = "json"
 	} else if strings.HasSuffix(o, ".yml") || strings.HasSuffix(o, ".yaml") {
 		f = "yaml"
+	} else if strings.HasSuffix(o, ".html") {
+		f = "html"
+	} else if strings.HasSuffix(o, ".md") {
+		f = "markdown"
 	} else {
 		if *format != "" {
 			switch *format {
@@ -54,51 +213,231 @@ func main() {
 				f = "json"
 			case "yaml", "yml":
 				f = "yaml"
+			case "html":
+				f = "html"
+			case "markdown", "md":
+				f = "markdown"
+			case "postman":
+				f = "postman"
+			case "jsonschema":
+				f = "jsonschema"
+			case "typescript", "ts":
+				f = "typescript"
 			default:
-				fmt.Println("goctl-openapi: format must be json or yaml")
+				fmt.Println("goctl-openapi: format must be json, yaml, html, markdown, postman, jsonschema or typescript")
 				return
 			}
 		}
 		if o != "-" {
-			o = fmt.Sprintf("%s.%s", o, f)
+			o = fmt.Sprintf("%s.%s", o, extensions[f])
 		}
 	}
 
-	var w *os.File
-	if o == "-" {
-		w = os.Stdout
-	} else {
-		w, err = os.Create(path.Join(p.Dir, o))
+	mdOpts := render.MarkdownOptions{HeadingLevel: *headingLevel}
+	if err = mdOpts.Validate(); err != nil {
+		fmt.Printf("goctl-openapi: %s\n", err)
+		return
+	}
+	if *embed != "" && o == "-" {
+		fmt.Println("goctl-openapi: embed requires an output file")
+		return
+	}
+
+	if *apiFile == "" {
+		if err = generate(p, opts, o, f, mdOpts); err != nil {
+			fmt.Printf("goctl-openapi: %s\n", err)
+		}
+		return
+	}
+
+	run := func() error {
+		// the parser exits the process on empty files, which are common when editors are saving files.
+		for _, f := range apiFiles(p.ApiFilePath) {
+			if info, err := os.Stat(f); err == nil && info.Size() == 0 {
+				return fmt.Errorf("%s is empty", f)
+			}
+		}
+		sp, err := newPlugin(p.ApiFilePath)
 		if err != nil {
+			return err
+		}
+		sp.Dir = p.Dir
+		return generate(sp, opts, o, f, mdOpts)
+	}
+	if !*watch {
+		if err = run(); err != nil {
+			fmt.Printf("goctl-openapi: %s\n", err)
+		}
+		return
+	}
+	watchFiles(p.ApiFilePath, func() {
+		// previous outputs are left untouched if generation fails.
+		if err := run(); err != nil {
 			fmt.Printf("goctl-openapi: %s\n", err)
 			return
 		}
-		defer w.Close()
+		if o != "-" {
+			fmt.Printf("goctl-openapi: generated %s\n", path.Join(p.Dir, o))
+		}
+	})
+}
+
+// applyConfig fills options which are not passed by command line flags from config file.
+func applyConfig(cfg *config.Config) {
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	if !set["filename"] && cfg.Output.Filename != "" {
+		*output = cfg.Output.Filename
+	}
+	if !set["format"] && cfg.Output.Format != "" {
+		*format = cfg.Output.Format
+	}
+	if !set["embed"] && cfg.Output.Embed != "" {
+		*embed = cfg.Output.Embed
+	}
+	if !set["http"] && cfg.Output.HTTP != "" {
+		*httpDir = cfg.Output.HTTP
+	}
+	if !set["jsonschema"] && cfg.Output.JSONSchema != "" {
+		*schemaDir = cfg.Output.JSONSchema
+	}
+	if !set["typescript"] && cfg.Output.TypeScript != "" {
+		*tsFile = cfg.Output.TypeScript
+	}
+	if !set["contract"] && cfg.Output.Contract != "" {
+		*contractDir = cfg.Output.Contract
 	}
+	if !set["heading-level"] && cfg.Output.HeadingLevel != 0 {
+		*headingLevel = cfg.Output.HeadingLevel
+	}
+	if !set["examples"] {
+		*examples = *examples || cfg.Examples
+	}
+	if !set["example-depth"] && cfg.ExampleDepth != 0 {
+		*exampleDepth = cfg.ExampleDepth
+	}
+	if !set["pretty"] {
+		*pretty = *pretty || cfg.Output.Pretty
+	}
+	// files from command line are applied later, so they take precedence.
+	merges = append(cfg.Merge[:len(cfg.Merge):len(cfg.Merge)], merges...)
+	overlays = append(cfg.Overlays[:len(cfg.Overlays):len(cfg.Overlays)], overlays...)
+	ruleFiles = append(cfg.Rules[:len(cfg.Rules):len(cfg.Rules)], ruleFiles...)
+}
 
-	doc, err := oas3.GetDoc(p)
+// generate writes the document of api and additional outputs, o is the output file name relative to p.Dir, f is the format.
+func generate(p *plugin.Plugin, opts oas3.Options, o, f string, mdOpts render.MarkdownOptions) error {
+	doc, diags, err := oas3.Generate(p.Api, opts)
 	if err != nil {
-		fmt.Printf("goctl-openapi: %s\n", err)
-		return
+		return err
+	}
+	for _, d := range diags {
+		fmt.Printf("goctl-openapi: %s\n", d)
 	}
 
-	if f == "json" {
+	if err = postProcess(doc, p.Dir, merges, overlays); err != nil {
+		return err
+	}
+	// examples are synthesized at last, so that schemas from fragments and overlays are honored.
+	if *examples {
+		oas3.FillExamples(doc, oas3.ExampleOptions{MaxDepth: *exampleDepth})
+	}
+
+	w := &bytes.Buffer{}
+	switch f {
+	case "html":
+		if err = render.HTML(w, doc); err != nil {
+			return err
+		}
+	case "markdown":
+		if err = render.Markdown(w, doc, mdOpts); err != nil {
+			return err
+		}
+	case "postman":
+		data, err := postman.New(doc).Marshal()
+		if err == nil {
+			_, err = w.Write(data)
+		}
+		if err != nil {
+			return err
+		}
+	case "jsonschema":
+		data, err := jsonschema.Bundle(doc.Info.Title, typeSchemas(p.Api, diags))
+		if err == nil {
+			_, err = w.Write(append(data, '\n'))
+		}
+		if err != nil {
+			return err
+		}
+	case "typescript":
+		if _, err = w.Write(typescript.Generate(doc)); err != nil {
+			return err
+		}
+	case "json":
 		encoder := json.NewEncoder(w)
 		if *pretty {
 			encoder.SetIndent("", "  ")
 		}
 		err = encoder.Encode(doc)
 		if err != nil {
-			fmt.Printf("goctl-openapi: %s\n", err)
-			return
+			return err
+		}
+	default:
+		// encode by json first, yaml.v2 can't encode openapi3.Paths which hides its map.
+		data, err := json.Marshal(doc)
+		if err == nil {
+			data, err = yaml.JSONToYAML(data)
+		}
+		if err == nil {
+			_, err = w.Write(data)
 		}
-	} else {
-		encoder := yaml.NewEncoder(w)
-		defer encoder.Close()
-		err = encoder.Encode(doc)
 		if err != nil {
-			fmt.Printf("goctl-openapi: %s\n", err)
-			return
+			return err
+		}
+	}
+
+	// the output file is written after the document is rendered, so that it's left untouched on failure.
+	if o == "-" {
+		_, err = os.Stdout.Write(w.Bytes())
+	} else if err = os.MkdirAll(path.Dir(path.Join(p.Dir, o)), 0o755); err == nil {
+		err = os.WriteFile(path.Join(p.Dir, o), w.Bytes(), 0o644)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *httpDir != "" {
+		if err = writeHTTPFiles(doc, config.ResolvePath(p.Dir, *httpDir)); err != nil {
+			return err
+		}
+	}
+
+	if *schemaDir != "" {
+		if err = writeJSONSchemaFiles(typeSchemas(p.Api, diags), config.ResolvePath(p.Dir, *schemaDir)); err != nil {
+			return err
+		}
+	}
+
+	if *tsFile != "" {
+		if err = os.WriteFile(config.ResolvePath(p.Dir, *tsFile), typescript.Generate(doc), 0o644); err != nil {
+			return err
+		}
+	}
+
+	if *contractDir != "" {
+		if err = writeContractFiles(doc, config.ResolvePath(p.Dir, *contractDir)); err != nil {
+			return err
+		}
+	}
+
+	if *embed != "" {
+		embedName := path.Join(path.Dir(path.Join(p.Dir, o)), *embed)
+		if err = writeEmbed(embedName, path.Join(p.Dir, o)); err != nil {
+			return err
 		}
 	}
+	return nil
 }
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// argsEnv holds the arguments when the test binary is re-executed as goctl-openapi.
+const argsEnv = "GOCTL_OPENAPI_ARGS"
+
+func TestMain(m *testing.M) {
+	if args := os.Getenv(argsEnv); args != "" {
+		os.Args = append([]string{"goctl-openapi"}, strings.Split(args, "\n")...)
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// command runs goctl-openapi with args in dir, stdout and stderr are returned separately.
+func command(t *testing.T, dir string, args ...string) (stdout, stderr string) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0])
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), argsEnv+"="+strings.Join(args, "\n"))
+	var out, errOut bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("goctl-openapi %s: %s\n%s%s", strings.Join(args, " "), err, out.String(), errOut.String())
+	}
+	return out.String(), errOut.String()
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDiffJSONWithUnmatchedOverlay(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"base.api": `syntax = "v1"
+
+type Book {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+service Books {
+	@handler GetBook
+	get /book returns (Book)
+}
+`,
+		"revision.api": `syntax = "v1"
+
+type Book {
+	Name string ` + "`json:\"name,optional\"`" + `
+}
+
+service Books {
+	@handler GetBook
+	get /book returns (Book)
+}
+`,
+		".goctl-openapi.yaml": "overlays: [overlay.yaml]\n",
+		"overlay.yaml": `overlay: 1.0.0
+info: {title: t, version: v1}
+actions:
+  - target: $.paths['/missing']
+    update: {description: missing}
+`,
+	})
+
+	stdout, stderr := command(t, dir, "diff", "-format", "json", "-fail-on", "none", "base.api", "revision.api")
+	var v struct {
+		Changes []json.RawMessage `json:"changes"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &v); err != nil {
+		t.Fatalf("stdout is not json: %s\n%s", err, stdout)
+	}
+	if len(v.Changes) == 0 {
+		t.Errorf("no changes reported: %s", stdout)
+	}
+	if !strings.Contains(stderr, "matched nothing") {
+		t.Errorf("stderr doesn't report unmatched action: %s", stderr)
+	}
+}
+
+func TestConfigRelativeToWorkingDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"api", "conf"} {
+		if err := os.Mkdir(filepath.Join(dir, sub), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFiles(t, dir, map[string]string{
+		"api/book.api": `syntax = "v1"
+
+type Book {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type Unused {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+service Books {
+	@handler GetBook
+	get /book returns (Book)
+}
+`,
+		"conf/openapi.yaml":  "allTypes: true\nmerge: [fragment.yaml]\n",
+		"conf/fragment.yaml": "info: {description: merged}\n",
+	})
+
+	stdout, stderr := command(t, dir, "-api", "api/book.api", "-config", "conf/openapi.yaml", "-filename", "-")
+	for _, want := range []string{`"Unused"`, `"description":"merged"`} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("output doesn't contain %s: %s%s", want, stdout, stderr)
+		}
+	}
+}
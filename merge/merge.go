@@ -0,0 +1,263 @@
+package merge
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/invopop/yaml"
+	"github.com/pkg/errors"
+)
+
+// Conflict is reported when a hand-written fragment defines an operation or
+// a component which is also generated from api file.
+type Conflict struct {
+	// Kind is "operation" or the components section, e.g. "schemas", "requestBodies".
+	Kind string
+	// Name is "METHOD /path" for operations, component name otherwise.
+	Name string
+	// Fields are json pointers (relative to the operation or component) of values defined by the fragment.
+	Fields []string
+	// Overridden are fields whose generated values are replaced by the fragment.
+	Overridden []string
+}
+
+func (c Conflict) String() string {
+	s := fmt.Sprintf("%s \"%s\" is also generated, fields from fragment: %s", c.Kind, c.Name, strings.Join(c.Fields, ", "))
+	if len(c.Overridden) > 0 {
+		s += fmt.Sprintf(", overridden fields: %s", strings.Join(c.Overridden, ", "))
+	}
+	return s
+}
+
+// File reads a partial openapi document in json or yaml format and merges it into doc.
+func File(doc *openapi3.T, filename string) ([]Conflict, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Merge(doc, data)
+}
+
+// Merge deep-merges a partial openapi document in json or yaml format into doc.
+//
+// Precedence rules:
+//   - objects are merged key by key recursively.
+//   - for scalars and arrays, the value from fragment replaces the generated one,
+//     except that root "tags" are merged by tag name.
+//   - if fragment defines an operation or a component which is also generated,
+//     a Conflict is reported with fields defined by the fragment and generated values overridden by it,
+//     the fragment values still win.
+func Merge(doc *openapi3.T, fragment []byte) ([]Conflict, error) {
+	fragmentJson, err := yaml.YAMLToJSON(fragment)
+	if err != nil {
+		return nil, errors.WithMessage(err, "parse fragment")
+	}
+	var src map[string]interface{}
+	if err = json.Unmarshal(fragmentJson, &src); err != nil {
+		return nil, errors.WithMessage(err, "parse fragment")
+	}
+
+	docJson, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var dst map[string]interface{}
+	if err = json.Unmarshal(docJson, &dst); err != nil {
+		return nil, err
+	}
+
+	m := merger{}
+	dst = m.mergeObject(dst, src, nil)
+
+	mergedJson, err := json.Marshal(dst)
+	if err != nil {
+		return nil, err
+	}
+	var merged openapi3.T
+	if err = json.Unmarshal(mergedJson, &merged); err != nil {
+		return nil, errors.WithMessage(err, "load merged document")
+	}
+	*doc = merged
+	return m.conflicts(), nil
+}
+
+type merger struct {
+	// json pointers of fragment values grouped by conflict
+	defined map[conflictKey][]string
+	// json pointers of overridden values grouped by conflict
+	overridden map[conflictKey][]string
+}
+
+type conflictKey struct {
+	kind string
+	name string
+}
+
+func (m *merger) mergeObject(dst, src map[string]interface{}, path []string) map[string]interface{} {
+	if dst == nil {
+		dst = make(map[string]interface{}, len(src))
+	}
+	for k, sv := range src {
+		p := append(path[:len(path):len(path)], k)
+		dv, ok := dst[k]
+		if !ok {
+			dst[k] = sv
+			continue
+		}
+		if key, ok := generated(p); ok {
+			if m.defined == nil {
+				m.defined = make(map[conflictKey][]string)
+			}
+			m.defined[key] = append(m.defined[key], leaves(sv, nil)...)
+		}
+
+		if len(p) == 1 && k == "tags" {
+			dst[k] = mergeTags(dv, sv)
+			continue
+		}
+
+		dm, dok := dv.(map[string]interface{})
+		sm, sok := sv.(map[string]interface{})
+		if dok && sok {
+			dst[k] = m.mergeObject(dm, sm, p)
+			continue
+		}
+
+		if !equal(dv, sv) {
+			m.override(p)
+		}
+		dst[k] = sv
+	}
+	return dst
+}
+
+// override records an overridden value if it belongs to an operation or a component.
+func (m *merger) override(path []string) {
+	if len(path) <= 3 {
+		return
+	}
+	key, ok := generated(path[:3])
+	if !ok {
+		return
+	}
+	if m.overridden == nil {
+		m.overridden = make(map[conflictKey][]string)
+	}
+	m.overridden[key] = append(m.overridden[key], pointer(path[3:]))
+}
+
+// generated returns the conflict key if path is an operation or a component.
+func generated(path []string) (conflictKey, bool) {
+	if len(path) != 3 {
+		return conflictKey{}, false
+	}
+	switch {
+	case path[0] == "paths" && isMethod(path[2]):
+		return conflictKey{kind: "operation", name: fmt.Sprintf("%s %s", strings.ToUpper(path[2]), path[1])}, true
+	case path[0] == "components":
+		return conflictKey{kind: path[1], name: path[2]}, true
+	}
+	return conflictKey{}, false
+}
+
+func (m *merger) conflicts() []Conflict {
+	conflicts := make([]Conflict, 0, len(m.defined))
+	for key, fields := range m.defined {
+		sort.Strings(fields)
+		overridden := m.overridden[key]
+		sort.Strings(overridden)
+		conflicts = append(conflicts, Conflict{
+			Kind:       key.kind,
+			Name:       key.name,
+			Fields:     fields,
+			Overridden: overridden,
+		})
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].Kind != conflicts[j].Kind {
+			return conflicts[i].Kind < conflicts[j].Kind
+		}
+		return conflicts[i].Name < conflicts[j].Name
+	})
+	return conflicts
+}
+
+func isMethod(s string) bool {
+	switch strings.ToUpper(s) {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// mergeTags merges root tags by name, tag fields from src take precedence.
+func mergeTags(dst, src interface{}) interface{} {
+	dl, dok := dst.([]interface{})
+	sl, sok := src.([]interface{})
+	if !dok || !sok {
+		return src
+	}
+
+	merged := make([]interface{}, len(dl))
+	copy(merged, dl)
+out:
+	for _, st := range sl {
+		sm, ok := st.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for i, dt := range merged {
+			dm, ok := dt.(map[string]interface{})
+			if ok && dm["name"] == sm["name"] {
+				for k, v := range sm {
+					dm[k] = v
+				}
+				merged[i] = dm
+				continue out
+			}
+		}
+		merged = append(merged, sm)
+	}
+	return merged
+}
+
+func equal(a, b interface{}) bool {
+	ab, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(ab) == string(bb)
+}
+
+// leaves returns json pointers of scalars and arrays in v, empty objects are leaves as well.
+func leaves(v interface{}, path []string) []string {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return []string{pointer(path)}
+	}
+	var ps []string
+	for k, mv := range m {
+		ps = append(ps, leaves(mv, append(path[:len(path):len(path)], k))...)
+	}
+	return ps
+}
+
+// pointer builds json pointer from path segments.
+func pointer(path []string) string {
+	var sb strings.Builder
+	for _, p := range path {
+		sb.WriteByte('/')
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(p, "~", "~0"), "/", "~1"))
+	}
+	return sb.String()
+}
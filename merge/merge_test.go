@@ -0,0 +1,145 @@
+package merge
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const generatedDoc = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Books", "version": "v1"},
+  "tags": [{"name": "book"}],
+  "paths": {
+    "/book/{id}": {
+      "get": {
+        "operationId": "GetBook",
+        "summary": "get book",
+        "responses": {"200": {"description": "A successful response."}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Book": {"type": "object", "properties": {"name": {"type": "string"}}}
+    }
+  }
+}`
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name      string
+		fragment  string
+		conflicts []Conflict
+		check     func(t *testing.T, doc *openapi3.T)
+	}{
+		{
+			name:     "new operation and schema",
+			fragment: "paths:\n  /book:\n    post:\n      operationId: CreateBook\n      responses:\n        '200':\n          description: ok\ncomponents:\n  schemas:\n    Author:\n      type: object\n",
+			check: func(t *testing.T, doc *openapi3.T) {
+				if doc.Paths.Find("/book") == nil || doc.Components.Schemas["Author"] == nil {
+					t.Error("new operation or schema is not merged")
+				}
+			},
+		},
+		{
+			name:     "new method of generated path",
+			fragment: "paths:\n  /book/{id}:\n    delete:\n      responses:\n        '204':\n          description: deleted\n",
+			check: func(t *testing.T, doc *openapi3.T) {
+				if doc.Paths.Find("/book/{id}").Delete == nil || doc.Paths.Find("/book/{id}").Get == nil {
+					t.Error("methods are not merged")
+				}
+			},
+		},
+		{
+			name:     "add keys to generated operation",
+			fragment: "paths:\n  /book/{id}:\n    get:\n      description: long description\n      x-rate-limit: 10\n",
+			conflicts: []Conflict{{
+				Kind:   "operation",
+				Name:   "GET /book/{id}",
+				Fields: []string{"/description", "/x-rate-limit"},
+			}},
+			check: func(t *testing.T, doc *openapi3.T) {
+				op := doc.Paths.Find("/book/{id}").Get
+				if op.Description != "long description" || op.Summary != "get book" {
+					t.Errorf("operation is not deep-merged: %+v", op)
+				}
+			},
+		},
+		{
+			name:     "override generated values",
+			fragment: "paths:\n  /book/{id}:\n    get:\n      summary: Get a book\ncomponents:\n  schemas:\n    Book:\n      properties:\n        name:\n          type: string\n          example: Go\n        isbn:\n          type: string\n",
+			conflicts: []Conflict{
+				{
+					Kind:       "operation",
+					Name:       "GET /book/{id}",
+					Fields:     []string{"/summary"},
+					Overridden: []string{"/summary"},
+				},
+				{
+					Kind:   "schemas",
+					Name:   "Book",
+					Fields: []string{"/properties/isbn/type", "/properties/name/example", "/properties/name/type"},
+				},
+			},
+			check: func(t *testing.T, doc *openapi3.T) {
+				if doc.Paths.Find("/book/{id}").Get.Summary != "Get a book" {
+					t.Error("fragment value doesn't win")
+				}
+				if len(doc.Components.Schemas["Book"].Value.Properties) != 2 {
+					t.Error("schema properties are not merged")
+				}
+			},
+		},
+		{
+			name:     "tags are merged by name",
+			fragment: `{"tags": [{"name": "book", "description": "books"}, {"name": "author"}]}`,
+			check: func(t *testing.T, doc *openapi3.T) {
+				if len(doc.Tags) != 2 || doc.Tags.Get("book").Description != "books" {
+					t.Errorf("tags = %+v", doc.Tags)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc openapi3.T
+			if err := json.Unmarshal([]byte(generatedDoc), &doc); err != nil {
+				t.Fatal(err)
+			}
+			conflicts, err := Merge(&doc, []byte(tt.fragment))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(conflicts) == 0 {
+				conflicts = nil
+			}
+			if !reflect.DeepEqual(conflicts, tt.conflicts) {
+				t.Errorf("conflicts = %+v, want %+v", conflicts, tt.conflicts)
+			}
+			tt.check(t, &doc)
+		})
+	}
+}
+
+func TestMergeInvalid(t *testing.T) {
+	var doc openapi3.T
+	if err := json.Unmarshal([]byte(generatedDoc), &doc); err != nil {
+		t.Fatal(err)
+	}
+	for _, fragment := range []string{"paths: [", `{"paths": {"/x": {"get": {"responses": 1}}}}`} {
+		if _, err := Merge(&doc, []byte(fragment)); err == nil {
+			t.Errorf("fragment %q is merged", fragment)
+		}
+	}
+}
+
+func TestConflictString(t *testing.T) {
+	c := Conflict{Kind: "operation", Name: "GET /book", Fields: []string{"/summary", "/x-a"}, Overridden: []string{"/summary"}}
+	want := `operation "GET /book" is also generated, fields from fragment: /summary, /x-a, overridden fields: /summary`
+	if c.String() != want {
+		t.Errorf("String() = %s, want %s", c.String(), want)
+	}
+}
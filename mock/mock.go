@@ -0,0 +1,292 @@
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/jayvynl/goctl-openapi/internal/docutil"
+	"github.com/jayvynl/goctl-openapi/sample"
+	"github.com/jayvynl/goctl-openapi/validation"
+	"github.com/pkg/errors"
+)
+
+// StatusHeader is the request header choosing documented response status, e.g. "X-Mock-Status: 404".
+const StatusHeader = "X-Mock-Status"
+
+type (
+	Options struct {
+		// Latency delays every response.
+		Latency time.Duration
+		// ErrorRate is the probability of responding with an error, from 0 to 1.
+		ErrorRate float64
+		// ErrorStatus is the status of injected errors, default 500.
+		// The documented response of the status is used if any, otherwise a plain text error.
+		ErrorStatus int
+		// SkipValidation doesn't validate requests against parameters and request bodies.
+		SkipValidation bool
+		// CORS allows requests from any origin, preflight requests are answered.
+		CORS bool
+		// MaxDepth is how many times a recursive schema is expanded in synthesized responses, default 1.
+		MaxDepth int
+	}
+
+	mock struct {
+		doc    *openapi3.T
+		router routers.Router
+		opts   Options
+		mu     sync.Mutex
+		rand   *rand.Rand
+	}
+)
+
+// Validate checks options.
+func (o Options) Validate() error {
+	if o.Latency < 0 {
+		return fmt.Errorf("invalid latency %s", o.Latency)
+	}
+	if o.ErrorRate < 0 || o.ErrorRate > 1 {
+		return fmt.Errorf("invalid error rate %g, expect 0 to 1", o.ErrorRate)
+	}
+	if o.ErrorStatus != 0 && (o.ErrorStatus < 400 || o.ErrorStatus > 599) {
+		return fmt.Errorf("invalid error status %d, expect 400 to 599", o.ErrorStatus)
+	}
+	if o.MaxDepth < 0 {
+		return fmt.Errorf("invalid max depth %d", o.MaxDepth)
+	}
+	return nil
+}
+
+// New returns a handler serving operations of doc with examples, or values synthesized from schemas.
+// The successful response with the lowest status is served, StatusHeader chooses another documented response.
+func New(doc *openapi3.T, opts Options) (http.Handler, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.ErrorStatus == 0 {
+		opts.ErrorStatus = http.StatusInternalServerError
+	}
+
+	// the loaded document has references resolved.
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	loaded, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		return nil, errors.WithMessage(err, "load openapi document")
+	}
+	if err = loaded.Validate(context.Background()); err != nil {
+		return nil, errors.WithMessage(err, "invalid openapi document")
+	}
+	// routes are served at root, servers are ignored.
+	loaded.Servers = nil
+	for _, item := range loaded.Paths.Map() {
+		item.Servers = nil
+		for _, op := range item.Operations() {
+			op.Servers = nil
+		}
+	}
+	router, err := legacy.NewRouter(loaded)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &mock{
+		doc:    loaded,
+		router: router,
+		opts:   opts,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	handler := m.serve
+	if !opts.SkipValidation {
+		middleware, err := validation.Middleware(spec, validation.Options{})
+		if err != nil {
+			return nil, err
+		}
+		handler = middleware(handler)
+	}
+	if opts.CORS {
+		handler = cors(handler)
+	}
+	return http.HandlerFunc(handler), nil
+}
+
+func (m *mock) serve(w http.ResponseWriter, r *http.Request) {
+	route, _, err := m.router.FindRoute(r)
+	if err != nil {
+		// the router only reports method not allowed for paths without parameters.
+		if allow := m.allowedMethods(r.URL.Path); len(allow) > 0 {
+			w.Header().Set("Allow", strings.Join(allow, ", "))
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		http.NotFound(w, r)
+		return
+	}
+
+	if m.opts.Latency > 0 {
+		select {
+		case <-time.After(m.opts.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	status := 0
+	if v := r.Header.Get(StatusHeader); v != "" {
+		// net/http panics on writing status out of 100 to 599.
+		if status, err = strconv.Atoi(v); err != nil || status < 100 || status > 599 {
+			http.Error(w, "invalid "+StatusHeader+" header, expect status 100 to 599", http.StatusBadRequest)
+			return
+		}
+	} else if m.injectError() {
+		status = m.opts.ErrorStatus
+	}
+
+	responses := route.Operation.Responses
+	var ref *openapi3.ResponseRef
+	if status == 0 {
+		status, ref = docutil.SuccessResponse(responses)
+	} else if ref = responses.Status(status); ref == nil && status < 400 {
+		http.Error(w, "undocumented status "+strconv.Itoa(status), http.StatusBadRequest)
+		return
+	}
+	if ref == nil || ref.Value == nil {
+		// error status without documented response.
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	m.write(w, status, ref.Value)
+}
+
+// allowedMethods returns methods of path templates matching path, ignoring the request method.
+func (m *mock) allowedMethods(path string) []string {
+	segments := strings.Split(path, "/")
+	seen := make(map[string]bool)
+	var allow []string
+	for template, item := range m.doc.Paths.Map() {
+		if !matchTemplate(strings.Split(template, "/"), segments) {
+			continue
+		}
+		for _, method := range docutil.Methods(item) {
+			if !seen[method] {
+				seen[method] = true
+				allow = append(allow, method)
+			}
+		}
+	}
+	sort.Strings(allow)
+	return allow
+}
+
+// matchTemplate reports whether path segments match segments of path template like "/books/{id}".
+func matchTemplate(template, segments []string) bool {
+	if len(template) != len(segments) {
+		return false
+	}
+	for i, t := range template {
+		if strings.HasPrefix(t, "{") && strings.HasSuffix(t, "}") {
+			if segments[i] == "" {
+				return false
+			}
+		} else if t != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// write writes the first json content of response, or any content type if there is no json content.
+func (m *mock) write(w http.ResponseWriter, status int, response *openapi3.Response) {
+	if len(response.Content) == 0 {
+		w.WriteHeader(status)
+		return
+	}
+	contentType := "application/json"
+	mt := response.Content.Get(contentType)
+	if mt == nil {
+		types := make([]string, 0, len(response.Content))
+		for t := range response.Content {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+		contentType = types[0]
+		mt = response.Content[contentType]
+	}
+
+	body := example(mt)
+	if body == nil {
+		body, _ = sample.Generate(m.doc, mt.Schema, sample.Options{MaxDepth: m.opts.MaxDepth, Response: true})
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	if s, ok := body.(string); ok && !strings.Contains(contentType, "json") {
+		_, _ = w.Write([]byte(s))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func (m *mock) injectError() bool {
+	if m.opts.ErrorRate == 0 {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.rand.Float64() < m.opts.ErrorRate
+}
+
+// example returns authored example of media type, the first named example in name order.
+func example(mt *openapi3.MediaType) interface{} {
+	if mt.Example != nil {
+		return mt.Example
+	}
+	names := make([]string, 0, len(mt.Examples))
+	for name := range mt.Examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if ref := mt.Examples[name]; ref != nil && ref.Value != nil && ref.Value.Value != nil {
+			return ref.Value.Value
+		}
+	}
+	return nil
+}
+
+// cors allows requests from any origin.
+func cors(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next(w, r)
+			return
+		}
+		header := w.Header()
+		header.Set("Access-Control-Allow-Origin", origin)
+		header.Add("Vary", "Origin")
+		header.Set("Access-Control-Allow-Credentials", "true")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			header.Set("Access-Control-Allow-Methods", r.Header.Get("Access-Control-Request-Method"))
+			if h := r.Header.Get("Access-Control-Request-Headers"); h != "" {
+				header.Set("Access-Control-Allow-Headers", h)
+			}
+			header.Set("Access-Control-Max-Age", "86400")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		header.Set("Access-Control-Expose-Headers", "*")
+		next(w, r)
+	}
+}
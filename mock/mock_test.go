@@ -0,0 +1,228 @@
+package mock
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const spec = `
+openapi: 3.0.3
+info: {title: books, version: v1}
+servers: [{url: "https://example.com/v1"}]
+paths:
+  /books/{id}:
+    get:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id, name]
+                properties: {id: {type: integer, example: 7}, name: {type: string, example: go}}
+        "404":
+          description: not found
+          content:
+            application/json:
+              examples:
+                b: {value: {message: b}}
+                a: {value: {message: missing}}
+    delete:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+      responses:
+        "204": {description: deleted}
+        "201": {description: created}
+  /ping:
+    get:
+      responses:
+        default:
+          description: pong
+          content:
+            text/plain:
+              schema: {type: string, example: pong}
+`
+
+func newHandler(t *testing.T, opts Options) http.Handler {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := New(doc, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestServe(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		status string
+		want   int
+		body   string
+	}{
+		{"synthesized", http.MethodGet, "/books/1", "", http.StatusOK, `{"id":7,"name":"go"}`},
+		{"chosen status", http.MethodGet, "/books/1", "404", http.StatusNotFound, `{"message":"missing"}`},
+		{"undocumented error", http.MethodGet, "/books/1", "503", http.StatusServiceUnavailable, "Service Unavailable"},
+		{"undocumented success", http.MethodGet, "/books/1", "202", http.StatusBadRequest, "undocumented status 202"},
+		{"informational", http.MethodGet, "/books/1", "100", http.StatusBadRequest, "undocumented status 100"},
+		{"not a number", http.MethodGet, "/books/1", "ok", http.StatusBadRequest, "invalid X-Mock-Status"},
+		{"status too low", http.MethodGet, "/books/1", "99", http.StatusBadRequest, "invalid X-Mock-Status"},
+		{"status too high", http.MethodGet, "/books/1", "600", http.StatusBadRequest, "invalid X-Mock-Status"},
+		{"negative status", http.MethodGet, "/books/1", "-200", http.StatusBadRequest, "invalid X-Mock-Status"},
+		{"lowest success", http.MethodDelete, "/books/1", "", http.StatusCreated, ""},
+		{"default response", http.MethodGet, "/ping", "", http.StatusOK, "pong"},
+		{"invalid request", http.MethodGet, "/books/abc", "", http.StatusBadRequest, `path parameter "id"`},
+		{"not found", http.MethodGet, "/authors", "", http.StatusNotFound, ""},
+		{"method not allowed", http.MethodPost, "/ping", "", http.StatusMethodNotAllowed, ""},
+		{"method not allowed with parameter", http.MethodPost, "/books/1", "", http.StatusMethodNotAllowed, ""},
+		{"empty parameter", http.MethodPost, "/books/", "", http.StatusNotFound, ""},
+	}
+	h := newHandler(t, Options{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.status != "" {
+				r.Header.Set(StatusHeader, tt.status)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d, body: %s", w.Code, tt.want, w.Body)
+			}
+			if !strings.Contains(w.Body.String(), tt.body) {
+				t.Errorf("body = %s, want %s", w.Body, tt.body)
+			}
+		})
+	}
+}
+
+func TestServeAllow(t *testing.T) {
+	tests := []struct {
+		method string
+		target string
+		allow  string
+	}{
+		{http.MethodPost, "/ping", "GET"},
+		{http.MethodPut, "/books/1", "DELETE, GET"},
+		{http.MethodPatch, "/books/abc", "DELETE, GET"},
+		{http.MethodPost, "/books/1/authors", ""},
+	}
+	h := newHandler(t, Options{})
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+		if got := w.Header().Get("Allow"); got != tt.allow {
+			t.Errorf("%s %s: Allow = %q, want %q", tt.method, tt.target, got, tt.allow)
+		}
+	}
+}
+
+func TestServeOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   Options
+		header map[string]string
+		method string
+		want   int
+		check  func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "injected error",
+			opts:   Options{ErrorRate: 1, ErrorStatus: http.StatusNotFound},
+			method: http.MethodGet,
+			want:   http.StatusNotFound,
+		},
+		{
+			name:   "status header wins over injected error",
+			opts:   Options{ErrorRate: 1},
+			header: map[string]string{StatusHeader: "200"},
+			method: http.MethodGet,
+			want:   http.StatusOK,
+		},
+		{
+			name:   "cors",
+			opts:   Options{CORS: true},
+			header: map[string]string{"Origin": "http://localhost:3000"},
+			method: http.MethodGet,
+			want:   http.StatusOK,
+			check: func(t *testing.T, w *httptest.ResponseRecorder) {
+				if got := w.Header().Get("Access-Control-Allow-Origin"); got != "http://localhost:3000" {
+					t.Errorf("Access-Control-Allow-Origin = %s", got)
+				}
+			},
+		},
+		{
+			name:   "preflight",
+			opts:   Options{CORS: true},
+			header: map[string]string{"Origin": "http://localhost:3000", "Access-Control-Request-Method": "DELETE"},
+			method: http.MethodOptions,
+			want:   http.StatusNoContent,
+			check: func(t *testing.T, w *httptest.ResponseRecorder) {
+				if got := w.Header().Get("Access-Control-Allow-Methods"); got != "DELETE" {
+					t.Errorf("Access-Control-Allow-Methods = %s", got)
+				}
+			},
+		},
+		{
+			name:   "latency",
+			opts:   Options{Latency: 20 * time.Millisecond},
+			method: http.MethodGet,
+			want:   http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/books/1", nil)
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			start := time.Now()
+			newHandler(t, tt.opts).ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d, body: %s", w.Code, tt.want, w.Body)
+			}
+			if elapsed := time.Since(start); elapsed < tt.opts.Latency {
+				t.Errorf("responded in %s, want latency %s", elapsed, tt.opts.Latency)
+			}
+			if tt.check != nil {
+				tt.check(t, w)
+			}
+		})
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		err  string
+	}{
+		{name: "zero"},
+		{name: "negative latency", opts: Options{Latency: -time.Second}, err: "invalid latency"},
+		{name: "error rate", opts: Options{ErrorRate: 1.5}, err: "invalid error rate"},
+		{name: "error status", opts: Options{ErrorStatus: 200}, err: "invalid error status"},
+		{name: "max depth", opts: Options{MaxDepth: -1}, err: "invalid max depth"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if tt.err == "" && err != nil || tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -40,7 +40,13 @@ func fillEnumFromOptions(s *openapi3.SchemaRef, options string) error {
 		err error
 	)
 
-	opts := strings.Split(options[1:len(options)-1], ",")
+	// options=foo|bar or options=[foo,bar]
+	var opts []string
+	if strings.HasPrefix(options, "[") && strings.HasSuffix(options, "]") {
+		opts = strings.Split(options[1:len(options)-1], ",")
+	} else {
+		opts = strings.Split(options, "|")
+	}
 	enum := make([]interface{}, len(opts))
 	for i, opt := range opts {
 		switch s.Value.Type {
@@ -0,0 +1,31 @@
+package oas3
+
+import "fmt"
+
+// Diagnostic is a problem found in api spec during generation,
+// the related part is skipped or generated partially.
+type Diagnostic struct {
+	// Location is the type member or route, e.g. "Book.Author", "post /book/:id".
+	Location string
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	if d.Location == "" {
+		return d.Message
+	}
+	return fmt.Sprintf("%s: %s", d.Location, d.Message)
+}
+
+type diagnostics []Diagnostic
+
+// add appends a diagnostic, the same type may be parsed multiple times, duplicates are ignored.
+func (ds *diagnostics) add(location, format string, args ...interface{}) {
+	d := Diagnostic{Location: location, Message: fmt.Sprintf(format, args...)}
+	for _, e := range *ds {
+		if e == d {
+			return
+		}
+	}
+	*ds = append(*ds, d)
+}
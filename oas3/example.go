@@ -0,0 +1,350 @@
+package oas3
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/invopop/yaml"
+	"github.com/jayvynl/goctl-openapi/constant"
+	"github.com/jayvynl/goctl-openapi/sample"
+	"github.com/pkg/errors"
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+)
+
+// ExampleOptions customize synthesized examples.
+type ExampleOptions struct {
+	// MaxDepth is how many times a recursive schema is expanded in an example, default 1.
+	MaxDepth int
+}
+
+// FillExamples synthesizes examples of component schemas, parameters, request bodies and responses of doc,
+// existing examples are kept, examples which don't satisfy schemas are skipped. Request examples exclude read only properties,
+// response examples exclude write only properties.
+func FillExamples(doc *openapi3.T, opts ExampleOptions) {
+	requests := sample.Options{MaxDepth: opts.MaxDepth}
+	responses := sample.Options{MaxDepth: opts.MaxDepth, Response: true}
+	// media types are filled before schemas, otherwise they copy schema examples.
+	visited := make(map[*openapi3.MediaType]bool)
+	fillContent := func(content openapi3.Content, opts sample.Options) {
+		for _, mt := range content {
+			if mt == nil || visited[mt] || mt.Schema == nil || mt.Example != nil || len(mt.Examples) > 0 {
+				continue
+			}
+			visited[mt] = true
+			if v, ok := sample.Generate(doc, mt.Schema, opts); ok {
+				mt.Example = v
+			}
+		}
+	}
+	fillParameters := func(params openapi3.Parameters) {
+		for _, p := range params {
+			if p == nil || p.Value == nil || p.Value.Schema == nil || p.Value.Example != nil || len(p.Value.Examples) > 0 {
+				continue
+			}
+			if v, ok := sample.Generate(doc, p.Value.Schema, requests); ok {
+				p.Value.Example = v
+			}
+		}
+	}
+
+	if doc.Components != nil {
+		for _, p := range doc.Components.Parameters {
+			fillParameters(openapi3.Parameters{p})
+		}
+		for _, rb := range doc.Components.RequestBodies {
+			if rb.Value != nil {
+				fillContent(rb.Value.Content, requests)
+			}
+		}
+		for _, r := range doc.Components.Responses {
+			if r.Value != nil {
+				fillContent(r.Value.Content, responses)
+			}
+		}
+	}
+	if doc.Paths != nil {
+		for _, item := range doc.Paths.Map() {
+			fillParameters(item.Parameters)
+			for _, op := range item.Operations() {
+				fillParameters(op.Parameters)
+				if op.RequestBody != nil && op.RequestBody.Value != nil {
+					fillContent(op.RequestBody.Value.Content, requests)
+				}
+				if op.Responses == nil {
+					continue
+				}
+				for _, r := range op.Responses.Map() {
+					if r.Value != nil {
+						fillContent(r.Value.Content, responses)
+					}
+				}
+			}
+		}
+	}
+
+	if doc.Components == nil {
+		return
+	}
+	// schema examples are response values, schemas are mostly read from responses.
+	examples := make(map[string]interface{}, len(doc.Components.Schemas))
+	for name, ref := range doc.Components.Schemas {
+		if ref.Value == nil || ref.Value.Example != nil {
+			continue
+		}
+		if v, ok := sample.Generate(doc, &openapi3.SchemaRef{Ref: "#/components/schemas/" + name}, responses); ok {
+			examples[name] = v
+		}
+	}
+	for name, example := range examples {
+		doc.Components.Schemas[name].Value.Example = example
+	}
+}
+
+// fillExample sets example from example tag of member, the example is validated against schema.
+// A reference is wrapped by allOf to carry the example, because siblings of $ref are ignored.
+func fillExample(s *openapi3.SchemaRef, tags []*spec.Tag, schemas openapi3.Schemas, loc string, diags *diagnostics) *openapi3.SchemaRef {
+	for _, tag := range tags {
+		if tag.Key != constant.TagKeyExample {
+			continue
+		}
+		// tag value is split by comma.
+		raw := strings.Join(append([]string{tag.Name}, tag.Options...), ",")
+		v, err := parseExample(s, raw, schemas)
+		if err != nil {
+			diags.add(loc, "invalid example \"%s\": %s", raw, err)
+			return s
+		}
+		if s.Value == nil {
+			s = &openapi3.SchemaRef{Value: &openapi3.Schema{AllOf: openapi3.SchemaRefs{s}}}
+		}
+		s.Value.Example = v
+		return s
+	}
+	return s
+}
+
+// fillRouteExamples sets examples of media types from @doc properties, e.g. "requestExample",
+// named examples are suffixed by name, e.g. "requestExample_minimal".
+// Content is copied if there is any example, because components are shared by routes.
+func fillRouteExamples(content openapi3.Content, properties map[string]string, prefix string, schemas openapi3.Schemas, loc string, diags *diagnostics) (openapi3.Content, bool) {
+	raws := make(map[string]string)
+	for key := range properties {
+		if key == prefix {
+			raws[""] = GetProperty(properties, key)
+		} else if strings.HasPrefix(key, prefix+"_") && len(key) > len(prefix)+1 {
+			raws[key[len(prefix)+1:]] = GetProperty(properties, key)
+		}
+	}
+	if len(raws) == 0 || len(content) == 0 {
+		return content, false
+	}
+	// a single unnamed example is emitted as example, otherwise it's named "default" in examples.
+	if raw, ok := raws[""]; ok && len(raws) > 1 {
+		delete(raws, "")
+		raws["default"] = raw
+	}
+
+	copied := make(openapi3.Content, len(content))
+	for contentType, mt := range content {
+		value := *mt
+		copied[contentType] = &value
+		for name, raw := range raws {
+			v, err := parseExample(mt.Schema, raw, schemas)
+			if err != nil {
+				key := prefix
+				if name != "" {
+					key += "_" + name
+				}
+				diags.add(loc, "invalid %s \"%s\": %s", key, raw, err)
+				continue
+			}
+			if name == "" {
+				value.Example = v
+				continue
+			}
+			if value.Examples == nil {
+				value.Examples = make(openapi3.Examples)
+			}
+			value.Examples[name] = &openapi3.ExampleRef{Value: openapi3.NewExample(v)}
+		}
+	}
+	return copied, true
+}
+
+// requestExamples returns request body with examples from @doc properties of route.
+func requestExamples(ref *openapi3.RequestBodyRef, requests openapi3.RequestBodies, properties map[string]string, schemas openapi3.Schemas, loc string, diags *diagnostics) *openapi3.RequestBodyRef {
+	target := ref
+	if target.Value == nil {
+		if target = requests[strings.TrimPrefix(ref.Ref, "#/components/requestBodies/")]; target == nil || target.Value == nil {
+			return ref
+		}
+	}
+	content, ok := fillRouteExamples(target.Value.Content, properties, "requestExample", schemas, loc, diags)
+	if !ok {
+		return ref
+	}
+	body := *target.Value
+	body.Content = content
+	return &openapi3.RequestBodyRef{Value: &body}
+}
+
+// responseExamples returns response with examples from @doc properties of route.
+func responseExamples(ref *openapi3.ResponseRef, responses openapi3.ResponseBodies, properties map[string]string, schemas openapi3.Schemas, loc string, diags *diagnostics) *openapi3.ResponseRef {
+	target := ref
+	if target.Value == nil {
+		if target = responses[strings.TrimPrefix(ref.Ref, "#/components/responses/")]; target == nil || target.Value == nil {
+			return ref
+		}
+	}
+	content, ok := fillRouteExamples(target.Value.Content, properties, "responseExample", schemas, loc, diags)
+	if !ok {
+		return ref
+	}
+	response := *target.Value
+	response.Content = content
+	return &openapi3.ResponseRef{Value: &response}
+}
+
+// parseExample parses raw example of schema and validates it. Scalars are parsed like default values,
+// other values are yaml or json documents, e.g. "{title: The Hobbit, tags: [fantasy]}",
+// scalars in documents are converted to types of their schemas.
+func parseExample(s *openapi3.SchemaRef, raw string, schemas openapi3.Schemas) (interface{}, error) {
+	r := &exampleResolver{schemas: schemas, resolved: make(map[*openapi3.Schema]*openapi3.Schema)}
+	schema := r.resolve(s).Value
+	if schema == nil {
+		return nil, fmt.Errorf("unresolved schema %s", s.Ref)
+	}
+
+	var (
+		v   interface{}
+		err error
+	)
+	switch schema.Type {
+	case openapi3.TypeBoolean, openapi3.TypeInteger, openapi3.TypeNumber, openapi3.TypeString:
+		v, err = ParseValue(schema.Type, schema.Format, raw)
+	default:
+		if err = yaml.Unmarshal([]byte(raw), &v); err == nil {
+			v, err = convertExample(schema, v)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = schema.VisitJSON(v); err != nil {
+		var se *openapi3.SchemaError
+		if errors.As(err, &se) {
+			if pointer := se.JSONPointer(); len(pointer) > 0 {
+				return nil, fmt.Errorf("\"/%s\": %s", strings.Join(pointer, "/"), se.Reason)
+			}
+			return nil, errors.New(se.Reason)
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+// convertExample converts scalars in v to types of their schemas, e.g. 1 of a string schema to "1".
+func convertExample(s *openapi3.Schema, v interface{}) (interface{}, error) {
+	if s == nil {
+		return v, nil
+	}
+	if len(s.AllOf) == 1 {
+		return convertExample(s.AllOf[0].Value, v)
+	}
+
+	var err error
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for name, field := range value {
+			ps := s.AdditionalProperties.Schema
+			if p, ok := s.Properties[name]; ok {
+				ps = p
+			}
+			if ps != nil {
+				if value[name], err = convertExample(ps.Value, field); err != nil {
+					return nil, errors.WithMessagef(err, "field \"%s\"", name)
+				}
+			}
+		}
+	case []interface{}:
+		if s.Items == nil {
+			return v, nil
+		}
+		for i, item := range value {
+			if value[i], err = convertExample(s.Items.Value, item); err != nil {
+				return nil, errors.WithMessagef(err, "item %d", i)
+			}
+		}
+	case string:
+		switch s.Type {
+		case openapi3.TypeBoolean, openapi3.TypeInteger, openapi3.TypeNumber:
+			return ParseValue(s.Type, s.Format, value)
+		}
+	case float64:
+		// large numbers like isbn are printed without exponent.
+		if s.Type == openapi3.TypeString {
+			return strconv.FormatFloat(value, 'f', -1, 64), nil
+		}
+	case bool:
+		if s.Type == openapi3.TypeString {
+			return strconv.FormatBool(value), nil
+		}
+	}
+	return v, nil
+}
+
+// exampleResolver copies schemas with references resolved, for validating examples.
+type exampleResolver struct {
+	schemas  openapi3.Schemas
+	resolved map[*openapi3.Schema]*openapi3.Schema
+}
+
+func (r *exampleResolver) resolve(ref *openapi3.SchemaRef) *openapi3.SchemaRef {
+	if ref == nil {
+		return nil
+	}
+	if ref.Value == nil {
+		target, ok := r.schemas[strings.TrimPrefix(ref.Ref, "#/components/schemas/")]
+		if !ok || target.Value == nil {
+			return ref
+		}
+		ref = target
+	}
+	return &openapi3.SchemaRef{Value: r.schema(ref.Value)}
+}
+
+// schema copies s, the copy is cached before resolving children for cyclic schemas.
+func (r *exampleResolver) schema(s *openapi3.Schema) *openapi3.Schema {
+	if c, ok := r.resolved[s]; ok {
+		return c
+	}
+	c := *s
+	r.resolved[s] = &c
+	c.Items = r.resolve(s.Items)
+	c.AdditionalProperties.Schema = r.resolve(s.AdditionalProperties.Schema)
+	c.Not = r.resolve(s.Not)
+	if s.Properties != nil {
+		c.Properties = make(openapi3.Schemas, len(s.Properties))
+		for name, p := range s.Properties {
+			c.Properties[name] = r.resolve(p)
+		}
+	}
+	c.AllOf = r.resolveAll(s.AllOf)
+	c.AnyOf = r.resolveAll(s.AnyOf)
+	c.OneOf = r.resolveAll(s.OneOf)
+	return &c
+}
+
+func (r *exampleResolver) resolveAll(refs openapi3.SchemaRefs) openapi3.SchemaRefs {
+	if refs == nil {
+		return nil
+	}
+	resolved := make(openapi3.SchemaRefs, len(refs))
+	for i, ref := range refs {
+		resolved[i] = r.resolve(ref)
+	}
+	return resolved
+}
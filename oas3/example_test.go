@@ -0,0 +1,128 @@
+package oas3
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/zeromicro/go-zero/tools/goctl/api/parser"
+)
+
+const examplesApi = `syntax = "v1"
+
+type Book {
+	Title string ` + "`json:\"title\" example:\"Go\"`" + `
+	Year  int    ` + "`json:\"year,range=[1000:3000]\"`" + `
+}
+
+type ListRequest {
+	Page    int    ` + "`form:\"page,default=2\"`" + `
+	Status  string ` + "`form:\"status,options=draft|published\"`" + `
+	Keyword string ` + "`form:\"keyword,optional\" example:\"go\"`" + `
+}
+
+service Books {
+	@handler ListBook
+	get /books (ListRequest) returns ([]Book)
+
+	@handler CreateBook
+	post /books (Book) returns (Book)
+}
+`
+
+func TestFillExamples(t *testing.T) {
+	api, err := parser.Parse(filepath.Join("testdata", "examples.api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tagged, _, err := Generate(api, DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	FillExamples(tagged, ExampleOptions{})
+	synthesized, _, err := Generate(parseApi(t, examplesApi), DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	FillExamples(synthesized, ExampleOptions{})
+
+	parameter := func(doc *openapi3.T, path, name string) interface{} {
+		for _, p := range doc.Paths.Value(path).Get.Parameters {
+			if p.Value.Name == name {
+				return p.Value.Example
+			}
+		}
+		return nil
+	}
+	mediaType := func(content openapi3.Content) *openapi3.MediaType {
+		return content.Get("application/json")
+	}
+	tests := []struct {
+		name string
+		got  interface{}
+		want string
+	}{
+		{
+			name: "request example of route",
+			got:  mediaType(tagged.Paths.Value("/book").Post.RequestBody.Value.Content).Example,
+			want: `{"isbn":"9780261102217","title":"The Hobbit","year":1937}`,
+		},
+		{
+			name: "named response examples of route",
+			got:  mediaType(tagged.Paths.Value("/book").Post.Responses.Status(200).Value.Content).Examples,
+			want: `{"default":{"value":{"title":"The Hobbit","year":1937}},"full":{"value":{"author":{"name":"J. R. R. Tolkien"},"tags":["fantasy"],"title":"The Hobbit","year":1937}}}`,
+		},
+		{
+			name: "named response examples are kept",
+			got:  mediaType(tagged.Paths.Value("/book").Post.Responses.Status(200).Value.Content).Example,
+			want: `null`,
+		},
+		{
+			name: "response from tags",
+			got:  mediaType(tagged.Components.Responses["Book"].Value.Content).Example,
+			want: `{"author":{"name":"J. R. R. Tolkien"},"isbn":"9780261102217","tags":["fantasy","adventure"],"title":"The Hobbit, or There and Back Again","year":1937}`,
+		},
+		{
+			name: "parameter from tag",
+			got:  parameter(tagged, "/book/{id}", "id"),
+			want: `42`,
+		},
+		{
+			name: "synthesized request",
+			got:  mediaType(synthesized.Components.RequestBodies["Book"].Value.Content).Example,
+			want: `{"title":"Go","year":1000}`,
+		},
+		{
+			name: "synthesized response",
+			got:  mediaType(synthesized.Paths.Value("/books").Get.Responses.Status(200).Value.Content).Example,
+			want: `[{"title":"Go","year":1000}]`,
+		},
+		{
+			name: "schema",
+			got:  synthesized.Components.Schemas["Book"].Value.Example,
+			want: `{"title":"Go","year":1000}`,
+		},
+		{
+			name: "default parameter",
+			got:  parameter(synthesized, "/books", "page"),
+			want: `2`,
+		},
+		{
+			name: "enum parameter",
+			got:  parameter(synthesized, "/books", "status"),
+			want: `"draft"`,
+		},
+		{
+			name: "parameter with example",
+			got:  parameter(synthesized, "/books", "keyword"),
+			want: `"go"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jsonOf(t, tt.got); got != tt.want {
+				t.Errorf("example = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package oas3
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -13,11 +14,15 @@ func getInfo(properties map[string]string) *openapi3.Info {
 	desc := GetProperty(properties, constant.ApiInfoDesc)
 	author := GetProperty(properties, constant.ApiInfoAuthor)
 	email := GetProperty(properties, constant.ApiInfoEmail)
+	date := strings.TrimSpace(GetProperty(properties, constant.ApiInfoDate))
 	info := &openapi3.Info{
 		Title:       title,
 		Description: desc,
 		Version:     version,
 	}
+	if date != "" {
+		info.Extensions = map[string]interface{}{constant.ExtensionDate: date}
+	}
 	if author != "" || email != "" {
 		info.Contact = &openapi3.Contact{
 			Name:  author,
@@ -62,3 +67,28 @@ func getTags(properties map[string]string) []string {
 
 	return strings.Split(names, ",")
 }
+
+// getDocumentTags returns tags declared by "tags" property of info.
+func getDocumentTags(properties map[string]string) openapi3.Tags {
+	names := getTags(properties)
+	if len(names) == 0 {
+		return nil
+	}
+
+	tags := make(openapi3.Tags, len(names))
+	for i, name := range names {
+		tags[i] = &openapi3.Tag{Name: name}
+	}
+	return tags
+}
+
+// getVisibility returns route visibility from "visibility" property, "internal" if internal property is true.
+func getVisibility(properties map[string]string) string {
+	if v := strings.TrimSpace(GetProperty(properties, constant.ApiInfoVisibility)); v != "" {
+		return v
+	}
+	if internal, _ := strconv.ParseBool(GetProperty(properties, constant.ApiInfoInternal)); internal {
+		return constant.VisibilityInternal
+	}
+	return ""
+}
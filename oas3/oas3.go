@@ -1,39 +1,110 @@
 package oas3
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jayvynl/goctl-openapi/constant"
+	"github.com/pkg/errors"
 	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
 	"github.com/zeromicro/go-zero/tools/goctl/plugin"
 )
 
 var DefaultResponseDesc = "A successful response."
 
+// GetDoc generates openapi document of plugin api with default options, diagnostics are printed.
 func GetDoc(p *plugin.Plugin) (*openapi3.T, error) {
+	return GetDocWithOptions(p, DefaultOptions())
+}
+
+// GetDocWithOptions generates openapi document of plugin api, diagnostics are printed.
+func GetDocWithOptions(p *plugin.Plugin, opts Options) (*openapi3.T, error) {
+	doc, diags, err := Generate(p.Api, opts)
+	for _, d := range diags {
+		fmt.Println(d)
+	}
+	return doc, err
+}
+
+// Generate generates openapi document of api spec, problems of api spec are returned as diagnostics,
+// the document is still generated without the problematic parts.
+func Generate(api *spec.ApiSpec, opts Options) (*openapi3.T, []Diagnostic, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	var diags diagnostics
 	doc := &openapi3.T{
 		OpenAPI:      "3.0.3",
 		Components:   newComponents(),
-		Info:         getInfo(p.Api.Info.Properties),
+		Info:         getInfo(api.Info.Properties),
 		Paths:        openapi3.NewPaths(),
-		Servers:      getServers(p.Api.Info.Properties),
-		ExternalDocs: getExternalDocs(p.Api.Info.Properties),
+		Servers:      getServers(api.Info.Properties),
+		ExternalDocs: getExternalDocs(api.Info.Properties),
+		Tags:         getDocumentTags(api.Info.Properties),
 	}
 
-	doc.Components.SecuritySchemes["jwt"] = &openapi3.SecuritySchemeRef{
-		Value: openapi3.NewJWTSecurityScheme(),
+	if opts.Version != "" {
+		doc.Info.Version = opts.Version
+	}
+
+	securityName := opts.Security.name()
+	doc.Components.SecuritySchemes[securityName] = &openapi3.SecuritySchemeRef{
+		Value: opts.Security.scheme(),
+	}
+	if opts.Security.Global {
+		doc.Security = []openapi3.SecurityRequirement{{securityName: []string{}}}
 	}
-	doc.Security = []openapi3.SecurityRequirement{{"jwt": []string{}}}
 
 	types := make(map[string]spec.DefineStruct) // all defined types from api spec
-	for _, typ := range p.Api.Types {
+	for _, typ := range api.Types {
+		if ds, ok := typ.(spec.DefineStruct); ok {
+			types[ds.Name()] = ds
+		}
+	}
+	operations := fillPaths(api, doc, opts, types, doc.Components.RequestBodies, doc.Components.Responses, doc.Components.Schemas, &diags)
+	for _, t := range opts.Transformers {
+		for _, op := range operations {
+			if err := t.Transform(doc, op); err != nil {
+				return nil, diags, errors.WithMessagef(err, "transform %s %s", strings.ToLower(op.Method), op.Route.Path)
+			}
+		}
+	}
+
+	// request bodies of routes without body, and embedded structs which are flattened,
+	// are registered in components but never referenced.
+	var roots []string
+	if opts.AllTypes {
+		for _, typ := range api.Types {
+			if ds, ok := typ.(spec.DefineStruct); ok {
+				getStructSchema(ds, types, doc.Components.Schemas, &diags)
+				roots = append(roots, fmt.Sprintf("#/components/schemas/%s", ds.Name()))
+			}
+		}
+	}
+	PruneComponents(doc, roots...)
+	return doc, diags, nil
+}
+
+// TypeSchemas returns schemas of all struct types defined in api spec, keyed by type name,
+// struct types are referenced by "#/components/schemas/<name>".
+func TypeSchemas(api *spec.ApiSpec) (openapi3.Schemas, []Diagnostic) {
+	var diags diagnostics
+	types := make(map[string]spec.DefineStruct)
+	for _, typ := range api.Types {
 		if ds, ok := typ.(spec.DefineStruct); ok {
 			types[ds.Name()] = ds
 		}
 	}
-	fillPaths(p, doc, types, doc.Components.RequestBodies, doc.Components.Responses, doc.Components.Schemas)
-	return doc, nil
+	schemas := make(openapi3.Schemas)
+	for _, typ := range api.Types {
+		if ds, ok := typ.(spec.DefineStruct); ok {
+			getStructSchema(ds, types, schemas, &diags)
+		}
+	}
+	return schemas, diags
 }
 
 func newComponents() *openapi3.Components {
@@ -51,30 +122,50 @@ func newComponents() *openapi3.Components {
 }
 
 func fillPaths(
-	p *plugin.Plugin,
+	api *spec.ApiSpec,
 	doc *openapi3.T,
+	opts Options,
 	types map[string]spec.DefineStruct, // all defined types from api spec
 	requests openapi3.RequestBodies, // request body references
 	responses openapi3.ResponseBodies, // response body references
 	schemas openapi3.Schemas, // schema references, json field of struct type will read and write this map
-) {
-	rp := newRequestParser()
+	diags *diagnostics, // problems found in api spec
+) []*OperationContext {
+	var operations []*OperationContext
+	rp := newRequestParser(diags)
+	errorResponses := parseErrorResponses(opts.ErrorResponses, opts.Envelope, types, responses, schemas, diags)
 
-	service := p.Api.Service.JoinPrefix()
+	service := api.Service.JoinPrefix()
 	for _, group := range service.Groups {
+		groupName := group.GetAnnotation("group")
 		for _, route := range group.Routes {
+			path := ConvertPath(route.Path)
 			method := strings.ToUpper(route.Method)
 			hasBody := method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
 
 			tags := getTags(route.AtDoc.Properties)
 			if len(tags) == 0 {
-				if gn := group.GetAnnotation("group"); len(gn) > 0 {
-					tags = []string{gn}
+				if len(groupName) > 0 {
+					tags = []string{groupName}
 				} else {
 					tags = []string{service.Name}
 				}
 			}
 
+			visibility := getVisibility(route.AtDoc.Properties)
+			if visibility == "" {
+				visibility = getVisibility(group.Annotation.Properties)
+			}
+			if !opts.Filter.match(routeInfo{
+				group:      groupName,
+				tags:       tags,
+				path:       path,
+				handler:    route.Handler,
+				visibility: visibility,
+			}) {
+				continue
+			}
+
 			summary := GetProperty(route.AtDoc.Properties, "summary")
 			if summary == "" {
 				summary = route.AtDoc.Text
@@ -101,19 +192,35 @@ func fillPaths(
 				responseTypeName = route.ResponseType.Name()
 			}
 
-			if responseTypeName == "" {
+			if responseTypeName != "" {
+				response = parseResponse(responseTypeName, opts.Envelope, types, responses, schemas, diags)
+			}
+			// no response type, or invalid response type
+			if response == nil {
 				response = &openapi3.ResponseRef{
 					Value: &openapi3.Response{
 						Description: &DefaultResponseDesc,
 					},
 				}
-			} else {
-				response = parseResponse(responseTypeName, types, responses, schemas)
+				if opts.Envelope != nil {
+					response.Value.Content = openapi3.NewContentWithJSONSchemaRef(wrapEnvelope(opts.Envelope, nil))
+				}
+			}
+
+			loc := fmt.Sprintf("%s %s", route.Method, route.Path)
+			if request != nil {
+				request = requestExamples(request, requests, route.AtDoc.Properties, schemas, loc, diags)
+			}
+			response = responseExamples(response, responses, route.AtDoc.Properties, schemas, loc, diags)
+
+			respOpts := []openapi3.NewResponsesOption{openapi3.WithStatus(http.StatusOK, response)}
+			for code, ref := range errorResponses {
+				respOpts = append(respOpts, openapi3.WithStatus(code, ref))
 			}
 
 			var security *openapi3.SecurityRequirements
 			if group.Annotation.Properties["jwt"] != "" {
-				security = &openapi3.SecurityRequirements{{"jwt": []string{}}}
+				security = &openapi3.SecurityRequirements{{opts.Security.name(): []string{}}}
 			}
 
 			var servers *openapi3.Servers
@@ -121,22 +228,31 @@ func fillPaths(
 				servers = &ss
 			}
 
-			doc.AddOperation(
-				ConvertPath(route.Path),
-				method,
-				&openapi3.Operation{
-					Tags:         tags,
-					Summary:      summary,
-					Description:  desc,
-					OperationID:  route.Handler,
-					Parameters:   params,
-					RequestBody:  request,
-					Responses:    openapi3.NewResponses(openapi3.WithStatus(http.StatusOK, response)),
-					Security:     security,
-					Servers:      servers,
-					ExternalDocs: getExternalDocs(route.AtDoc.Properties),
-				},
-			)
+			operation := &openapi3.Operation{
+				Tags:         tags,
+				Summary:      summary,
+				Description:  desc,
+				OperationID:  ConvertName(opts.Naming.OperationID, route.Handler),
+				Parameters:   params,
+				RequestBody:  request,
+				Responses:    openapi3.NewResponses(respOpts...),
+				Security:     security,
+				Servers:      servers,
+				ExternalDocs: getExternalDocs(route.AtDoc.Properties),
+				Extensions:   make(map[string]interface{}),
+			}
+			if visibility != "" {
+				operation.Extensions[constant.ExtensionVisibility] = visibility
+			}
+			doc.AddOperation(path, method, operation)
+			operations = append(operations, &OperationContext{
+				Group:     group,
+				Route:     route,
+				Path:      path,
+				Method:    method,
+				Operation: operation,
+			})
 		}
 	}
+	return operations
 }
@@ -0,0 +1,226 @@
+package oas3
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jayvynl/goctl-openapi/internal/golden"
+	"github.com/zeromicro/go-zero/tools/goctl/api/parser"
+)
+
+func TestGetDoc(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, filename := range files {
+		name := strings.TrimSuffix(filepath.Base(filename), ".api")
+		t.Run(name, func(t *testing.T) {
+			data, err := json.MarshalIndent(getDoc(t, filename), "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden.Check(t, filepath.Join("testdata", name+".json"), append(data, '\n'))
+		})
+	}
+}
+
+// TestExample checks the example openapi file is up to date.
+func TestExample(t *testing.T) {
+	data, err := json.Marshal(getDoc(t, filepath.Join("..", "example", "example.api")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden.Check(t, filepath.Join("..", "example", "openapi.json"), data)
+}
+
+func getDoc(t *testing.T, filename string) *openapi3.T {
+	t.Helper()
+	api, err := parser.Parse(filename)
+	if err != nil {
+		t.Fatalf("parse %s: %s", filename, err)
+	}
+	doc, diags, err := Generate(api, DefaultOptions())
+	if err != nil {
+		t.Fatalf("generate %s: %s", filename, err)
+	}
+	for _, d := range diags {
+		t.Errorf("diagnostic: %s", d)
+	}
+
+	// references of generated document are not resolved, validate the loaded document.
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loader := openapi3.NewLoader()
+	loaded, err := loader.LoadFromData(data)
+	if err != nil {
+		t.Fatalf("load %s: %s", filename, err)
+	}
+	if err = loaded.Validate(loader.Context); err != nil {
+		t.Errorf("invalid document: %s", err)
+	}
+	return doc
+}
+
+const optionsApi = `syntax = "v1"
+
+type Book {
+	Name string ` + "`json:\"name\" validate:\"min=a\"`" + `
+	Kind string ` + "`json:\"kind,options=a|b\" validate:\"required|min=1\"`" + `
+}
+
+type Error {
+	Detail string ` + "`json:\"detail\"`" + `
+}
+
+@server (
+	jwt: Auth
+)
+service Books {
+	@doc (
+		responseExample: "{name: 1"
+	)
+	@handler GetHTTPBook_v2
+	get /book returns (Book)
+}
+
+service Books {
+	@handler Ping
+	get /ping
+}
+`
+
+// jsonOf returns compact json of v.
+func jsonOf(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestGenerateEnvelope(t *testing.T) {
+	doc, _, err := Generate(parseApi(t, optionsApi), Options{
+		Envelope: &EnvelopeOptions{CodeField: "errcode", MessageField: "errmsg", DataField: "result"},
+		ErrorResponses: map[int]ErrorResponse{
+			400: {},
+			500: {Type: "Error", Description: "server error"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const envelope = `"properties":{"errcode":{"format":"int32","type":"integer"},"errmsg":{"type":"string"}`
+	tests := []struct {
+		name string
+		got  interface{}
+		want string
+	}{
+		{
+			name: "response",
+			got:  doc.Paths.Value("/book").Get.Responses.Status(200).Value.Content.Get("application/json").Schema,
+			want: `{` + envelope + `,"result":{"$ref":"#/components/schemas/Book"}},"required":["errcode","errmsg","result"],"type":"object"}`,
+		},
+		{
+			name: "empty response",
+			got:  doc.Paths.Value("/ping").Get.Responses.Status(200).Value.Content.Get("application/json").Schema,
+			want: `{` + envelope + `},"required":["errcode","errmsg"],"type":"object"}`,
+		},
+		{
+			name: "error response without type",
+			got:  doc.Components.Responses["BadRequest"],
+			want: `{"content":{"application/json":{"schema":{` + envelope + `},"required":["errcode","errmsg"],"type":"object"}}},"description":"Bad Request"}`,
+		},
+		{
+			name: "error response with type",
+			got:  doc.Components.Responses["InternalServerError"],
+			want: `{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Error"}}},"description":"server error"}`,
+		},
+		{
+			name: "error responses of operation",
+			got:  doc.Paths.Value("/ping").Get.Responses.Map(),
+			want: `{"200":` + jsonOf(t, doc.Paths.Value("/ping").Get.Responses.Status(200)) + `,"400":{"$ref":"#/components/responses/BadRequest"},"500":{"$ref":"#/components/responses/InternalServerError"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jsonOf(t, tt.got); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateNaming(t *testing.T) {
+	api := parseApi(t, optionsApi)
+	for style, want := range map[string]string{
+		"":       "GetHTTPBook_v2,Ping",
+		"camel":  "getHttpBookV2,ping",
+		"pascal": "GetHttpBookV2,Ping",
+		"snake":  "get_http_book_v2,ping",
+		"kebab":  "get-http-book-v2,ping",
+	} {
+		if got := operationIDs(t, api, Options{Naming: NamingOptions{OperationID: style}}); got != want {
+			t.Errorf("operationIds of style %q = %s, want %s", style, got, want)
+		}
+	}
+}
+
+func TestGenerateSecurity(t *testing.T) {
+	api := parseApi(t, optionsApi)
+	tests := []struct {
+		name   string
+		opts   SecurityOptions
+		global string
+		scheme string
+	}{
+		{"global", SecurityOptions{Global: true}, `[{"jwt":[]}]`, `{"bearerFormat":"JWT","scheme":"bearer","type":"http"}`},
+		{"jwt groups only", SecurityOptions{Name: "token", Scheme: openapi3.NewCSRFSecurityScheme()}, `null`, `{"in":"header","name":"X-XSRF-TOKEN","type":"apiKey"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, _, err := Generate(api, Options{Security: tt.opts})
+			if err != nil {
+				t.Fatal(err)
+			}
+			name := tt.opts.name()
+			if got := jsonOf(t, doc.Security); got != tt.global {
+				t.Errorf("security = %s, want %s", got, tt.global)
+			}
+			if got := jsonOf(t, doc.Components.SecuritySchemes[name]); got != tt.scheme {
+				t.Errorf("security scheme = %s, want %s", got, tt.scheme)
+			}
+			if got, want := jsonOf(t, doc.Paths.Value("/book").Get.Security), `[{"`+name+`":[]}]`; got != want {
+				t.Errorf("security of jwt group = %s, want %s", got, want)
+			}
+			if doc.Paths.Value("/ping").Get.Security != nil {
+				t.Errorf("security of group without jwt = %s, want null", jsonOf(t, doc.Paths.Value("/ping").Get.Security))
+			}
+		})
+	}
+}
+
+func TestGenerateDiagnostics(t *testing.T) {
+	_, diags, err := Generate(parseApi(t, optionsApi), Options{
+		ErrorResponses: map[int]ErrorResponse{503: {Type: "Missing"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Diagnostic{
+		{"", `invalid type "Missing" of error response 503: invalid type`},
+		{"Book.Name", `invalid validate tag "min=a": strconv.ParseUint: parsing "a": invalid syntax`},
+		{"Book.Kind", `validate tag which contains "|" is not supported`},
+		{"get /book", `invalid responseExample "{name: 1": error converting YAML to JSON: yaml: line 1: did not find expected ',' or '}'`},
+	}
+	if !reflect.DeepEqual(diags, want) {
+		t.Errorf("diagnostics = %q, want %q", diags, want)
+	}
+}
@@ -0,0 +1,216 @@
+package oas3
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/pkg/errors"
+)
+
+type (
+	// Options customize the generated document.
+	Options struct {
+		// Version overrides the version in api info.
+		Version  string
+		Security SecurityOptions
+		// Envelope wraps response bodies, nil means response bodies are not wrapped.
+		Envelope *EnvelopeOptions
+		// ErrorResponses are added to every operation, keyed by http status code.
+		ErrorResponses map[int]ErrorResponse
+		Naming         NamingOptions
+		Filter         FilterOptions
+		// AllTypes emits schemas of all types defined in api file, even if they are not used by any route.
+		AllTypes bool
+		// Transformers customize generated operations in order.
+		Transformers []Transformer
+	}
+
+	SecurityOptions struct {
+		// Name of the security scheme, default "jwt".
+		Name string
+		// Scheme used by groups with jwt annotation, default http bearer scheme with JWT format.
+		Scheme *openapi3.SecurityScheme
+		// Global requires the security scheme for all operations by default.
+		Global bool
+	}
+
+	// EnvelopeOptions describe the common response wrapper, e.g. {"code": 0, "msg": "ok", "data": {}}.
+	EnvelopeOptions struct {
+		CodeField    string
+		MessageField string
+		DataField    string
+	}
+
+	ErrorResponse struct {
+		Description string
+		// Type is the name of api type used as response body,
+		// empty means an object with code and message fields.
+		Type string
+	}
+
+	NamingOptions struct {
+		// OperationID is the naming style of operationId derived from handler name,
+		// one of "camel", "pascal", "snake", "kebab", empty keeps handler name unchanged.
+		OperationID string
+	}
+
+	// FilterOptions select routes to be included in the document,
+	// a route is included if it matches any include rule (or there is no include rule),
+	// and doesn't match any exclude rule. Unreachable components are pruned after filtering.
+	FilterOptions struct {
+		Include FilterRule
+		Exclude FilterRule
+	}
+
+	// FilterRule matches routes, patterns except Paths are glob patterns, see path.Match.
+	FilterRule struct {
+		// Groups are group names from group annotation of @server.
+		Groups []string
+		// Tags are operation tags.
+		Tags []string
+		// Paths are glob patterns of route paths in openapi format, e.g. "/book/{id}", "/book/*",
+		// see path.Match, a trailing "/**" matches any sub path.
+		Paths []string
+		// Handlers are handler names.
+		Handlers []string
+		// Visibility from "visibility" property of @doc or @server, "internal" if internal property is true.
+		Visibility []string
+	}
+
+	// routeInfo is the route attributes matched by filter rules.
+	routeInfo struct {
+		group      string
+		tags       []string
+		path       string
+		handler    string
+		visibility string
+	}
+)
+
+// DefaultOptions returns options which generate the same document as previous versions.
+func DefaultOptions() Options {
+	return Options{
+		Security: SecurityOptions{
+			Name:   "jwt",
+			Scheme: openapi3.NewJWTSecurityScheme(),
+			Global: true,
+		},
+	}
+}
+
+// Validate checks options are valid, zero value of options is valid.
+func (o Options) Validate() error {
+	if o.Security.Scheme != nil {
+		if err := o.Security.Scheme.Validate(context.Background()); err != nil {
+			return errors.WithMessage(err, "security scheme")
+		}
+	}
+	for code := range o.ErrorResponses {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("invalid status code %d of error response", code)
+		}
+	}
+	switch o.Naming.OperationID {
+	case "", "camel", "pascal", "snake", "kebab":
+	default:
+		return fmt.Errorf("invalid operationId naming style \"%s\"", o.Naming.OperationID)
+	}
+	return nil
+}
+
+func (so SecurityOptions) name() string {
+	if so.Name == "" {
+		return "jwt"
+	}
+	return so.Name
+}
+
+func (so SecurityOptions) scheme() *openapi3.SecurityScheme {
+	if so.Scheme == nil {
+		return openapi3.NewJWTSecurityScheme()
+	}
+	return so.Scheme
+}
+
+func (eo *EnvelopeOptions) fields() (code, message, data string) {
+	code, message, data = "code", "msg", "data"
+	if eo.CodeField != "" {
+		code = eo.CodeField
+	}
+	if eo.MessageField != "" {
+		message = eo.MessageField
+	}
+	if eo.DataField != "" {
+		data = eo.DataField
+	}
+	return
+}
+
+func (fo FilterOptions) empty() bool {
+	return fo.Include.empty() && fo.Exclude.empty()
+}
+
+func (fo FilterOptions) match(ri routeInfo) bool {
+	if !fo.Include.empty() && !fo.Include.match(ri) {
+		return false
+	}
+	return !fo.Exclude.match(ri)
+}
+
+// Set adds pattern from expression "kind:pattern", kind is one of group, tag, path, handler and visibility.
+func (fr *FilterRule) Set(expr string) error {
+	kind, pattern, ok := strings.Cut(expr, ":")
+	if !ok || pattern == "" {
+		return fmt.Errorf("invalid filter \"%s\", expect \"kind:pattern\"", expr)
+	}
+	switch kind {
+	case "group":
+		fr.Groups = append(fr.Groups, pattern)
+	case "tag":
+		fr.Tags = append(fr.Tags, pattern)
+	case "path":
+		fr.Paths = append(fr.Paths, pattern)
+	case "handler":
+		fr.Handlers = append(fr.Handlers, pattern)
+	case "visibility":
+		fr.Visibility = append(fr.Visibility, pattern)
+	default:
+		return fmt.Errorf("invalid filter kind \"%s\", expect one of group, tag, path, handler and visibility", kind)
+	}
+	return nil
+}
+
+func (fr FilterRule) empty() bool {
+	return len(fr.Groups) == 0 && len(fr.Tags) == 0 && len(fr.Paths) == 0 &&
+		len(fr.Handlers) == 0 && len(fr.Visibility) == 0
+}
+
+func (fr FilterRule) match(ri routeInfo) bool {
+	for _, pattern := range fr.Paths {
+		if MatchPath(pattern, ri.path) {
+			return true
+		}
+	}
+	for _, tag := range ri.tags {
+		if matchAny(fr.Tags, tag) {
+			return true
+		}
+	}
+	return matchAny(fr.Groups, ri.group) || matchAny(fr.Handlers, ri.handler) || matchAny(fr.Visibility, ri.visibility)
+}
+
+// matchAny reports whether s matches any pattern, empty s (e.g. route without group) matches nothing.
+func matchAny(patterns []string, s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, s); ok {
+			return true
+		}
+	}
+	return false
+}
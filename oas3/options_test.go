@@ -0,0 +1,154 @@
+package oas3
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/zeromicro/go-zero/tools/goctl/api/parser"
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+)
+
+const filterApi = `syntax = "v1"
+
+type Book {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+@server (
+	group: book
+)
+service Books {
+	@doc (
+		tags: "book,public"
+	)
+	@handler GetBook
+	get /book/:id returns (Book)
+
+	@handler ListBook
+	get /book returns (Book)
+}
+
+@server (
+	group: admin
+)
+service Books {
+	@doc (
+		internal: "true"
+	)
+	@handler Stats
+	get /admin/stats returns (Book)
+
+	@doc (
+		visibility: "beta"
+	)
+	@handler Reindex
+	post /admin/book/:id/reindex returns (Book)
+}
+
+service Books {
+	@handler Ping
+	get /ping
+}
+`
+
+func parseApi(t *testing.T, content string) *spec.ApiSpec {
+	t.Helper()
+	api, err := parser.ParseContent(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return api
+}
+
+// operationIDs returns sorted operation ids of the document generated with opts.
+func operationIDs(t *testing.T, api *spec.ApiSpec, opts Options) string {
+	t.Helper()
+	doc, _, err := Generate(api, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, item := range doc.Paths.Map() {
+		for _, op := range item.Operations() {
+			ids = append(ids, op.OperationID)
+		}
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+func TestFilter(t *testing.T) {
+	api := parseApi(t, filterApi)
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    string
+	}{
+		{"no filter", nil, nil, "GetBook,ListBook,Ping,Reindex,Stats"},
+		{"include group", []string{"group:book"}, nil, "GetBook,ListBook"},
+		{"exclude group", nil, []string{"group:admin"}, "GetBook,ListBook,Ping"},
+		{"group pattern doesn't match routes without group", nil, []string{"group:*"}, "Ping"},
+		{"include tag", []string{"tag:public"}, nil, "GetBook"},
+		{"tag from group", []string{"tag:admin"}, nil, "Reindex,Stats"},
+		{"tag from service", []string{"tag:Books"}, nil, "Ping"},
+		{"exclude tag", nil, []string{"tag:public"}, "ListBook,Ping,Reindex,Stats"},
+		{"include handler", []string{"handler:*Book"}, nil, "GetBook,ListBook"},
+		{"exclude handler", nil, []string{"handler:Ping"}, "GetBook,ListBook,Reindex,Stats"},
+		{"internal property", []string{"visibility:internal"}, nil, "Stats"},
+		{"exclude visibility", nil, []string{"visibility:*"}, "GetBook,ListBook,Ping"},
+		{"path", []string{"path:/book/{id}"}, nil, "GetBook"},
+		{"path glob", []string{"path:/book/*"}, nil, "GetBook"},
+		{"path sub paths", []string{"path:/book/**"}, nil, "GetBook,ListBook"},
+		{"path sub paths of glob", []string{"path:/*/book/**"}, nil, "Reindex"},
+		{"include any rule", []string{"group:book", "handler:Stats"}, nil, "GetBook,ListBook,Stats"},
+		{"exclude overrides include", []string{"path:/admin/**"}, []string{"visibility:internal"}, "Reindex"},
+		{"exclude overlapping include", []string{"group:book"}, []string{"group:book"}, ""},
+		{"exclude one kind of included", []string{"tag:book"}, []string{"path:/book/*"}, "ListBook"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{}
+			for _, expr := range tt.include {
+				if err := opts.Filter.Include.Set(expr); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, expr := range tt.exclude {
+				if err := opts.Filter.Exclude.Set(expr); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := operationIDs(t, api, opts); got != tt.want {
+				t.Errorf("operations = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterRuleSet(t *testing.T) {
+	var fr FilterRule
+	for _, expr := range []string{"group:book", "tag:a:b", "path:/book/**", "handler:Get*", "visibility:internal"} {
+		if err := fr.Set(expr); err != nil {
+			t.Errorf("Set(%s) = %s", expr, err)
+		}
+	}
+	want := FilterRule{
+		Groups:     []string{"book"},
+		Tags:       []string{"a:b"},
+		Paths:      []string{"/book/**"},
+		Handlers:   []string{"Get*"},
+		Visibility: []string{"internal"},
+	}
+	if !reflect.DeepEqual(fr, want) {
+		t.Errorf("rule = %+v, want %+v", fr, want)
+	}
+
+	for _, expr := range []string{"", "book", "group", "group:", ":book", "method:get", "Group:book"} {
+		if err := fr.Set(expr); err == nil {
+			t.Errorf("Set(%s) succeeded", expr)
+		}
+	}
+}
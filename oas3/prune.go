@@ -0,0 +1,145 @@
+package oas3
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const componentsRefPrefix = "#/components/"
+
+// PruneComponents removes schemas, parameters, headers, request bodies, responses, examples,
+// links and callbacks which are not reachable from paths or roots. Security schemes are kept.
+// roots are component references, e.g. "#/components/schemas/Book".
+func PruneComponents(doc *openapi3.T, roots ...string) {
+	if doc.Components == nil {
+		return
+	}
+
+	components := doc.Components
+	doc.Components = nil
+	reached := make(map[string]bool)
+	queue := collectRefs(doc, reached, nil)
+	doc.Components = components
+	for _, ref := range roots {
+		if !reached[ref] {
+			reached[ref] = true
+			queue = append(queue, ref)
+		}
+	}
+
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		if c := lookupComponent(components, ref); c != nil {
+			queue = collectRefs(c, reached, queue)
+		}
+	}
+
+	for name := range components.Schemas {
+		if !reached[componentsRefPrefix+"schemas/"+name] {
+			delete(components.Schemas, name)
+		}
+	}
+	for name := range components.Parameters {
+		if !reached[componentsRefPrefix+"parameters/"+name] {
+			delete(components.Parameters, name)
+		}
+	}
+	for name := range components.Headers {
+		if !reached[componentsRefPrefix+"headers/"+name] {
+			delete(components.Headers, name)
+		}
+	}
+	for name := range components.RequestBodies {
+		if !reached[componentsRefPrefix+"requestBodies/"+name] {
+			delete(components.RequestBodies, name)
+		}
+	}
+	for name := range components.Responses {
+		if !reached[componentsRefPrefix+"responses/"+name] {
+			delete(components.Responses, name)
+		}
+	}
+	for name := range components.Examples {
+		if !reached[componentsRefPrefix+"examples/"+name] {
+			delete(components.Examples, name)
+		}
+	}
+	for name := range components.Links {
+		if !reached[componentsRefPrefix+"links/"+name] {
+			delete(components.Links, name)
+		}
+	}
+	for name := range components.Callbacks {
+		if !reached[componentsRefPrefix+"callbacks/"+name] {
+			delete(components.Callbacks, name)
+		}
+	}
+}
+
+func lookupComponent(components *openapi3.Components, ref string) interface{} {
+	section, name, found := strings.Cut(strings.TrimPrefix(ref, componentsRefPrefix), "/")
+	if !found {
+		return nil
+	}
+	var (
+		c  interface{}
+		ok bool
+	)
+	switch section {
+	case "schemas":
+		c, ok = components.Schemas[name]
+	case "parameters":
+		c, ok = components.Parameters[name]
+	case "headers":
+		c, ok = components.Headers[name]
+	case "requestBodies":
+		c, ok = components.RequestBodies[name]
+	case "responses":
+		c, ok = components.Responses[name]
+	case "examples":
+		c, ok = components.Examples[name]
+	case "links":
+		c, ok = components.Links[name]
+	case "callbacks":
+		c, ok = components.Callbacks[name]
+	}
+	if !ok {
+		return nil
+	}
+	return c
+}
+
+// collectRefs appends component references in v which are not reached yet to queue.
+func collectRefs(v interface{}, reached map[string]bool, queue []string) []string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return queue
+	}
+	var value interface{}
+	if err = json.Unmarshal(data, &value); err != nil {
+		return queue
+	}
+
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			if ref, ok := t["$ref"].(string); ok && strings.HasPrefix(ref, componentsRefPrefix) && !reached[ref] {
+				reached[ref] = true
+				queue = append(queue, ref)
+			}
+			for _, e := range t {
+				walk(e)
+			}
+		case []interface{}:
+			for _, e := range t {
+				walk(e)
+			}
+		}
+	}
+	walk(value)
+	return queue
+}
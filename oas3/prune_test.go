@@ -0,0 +1,149 @@
+package oas3
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const pruneApi = `syntax = "v1"
+
+type Author {
+	Name  string  ` + "`json:\"name\"`" + `
+	Books []*Book ` + "`json:\"books,optional\"`" + `
+}
+
+type Book {
+	Title  string  ` + "`json:\"title\"`" + `
+	Author *Author ` + "`json:\"author,optional\"`" + `
+}
+
+type Filter {
+	Keyword string ` + "`json:\"keyword\"`" + `
+}
+
+type SearchRequest {
+	ID     int64  ` + "`path:\"id\"`" + `
+	Filter Filter ` + "`json:\"filter\"`" + `
+}
+
+type Unused {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+service Books {
+	@handler GetBook
+	get /books/:id (SearchRequest) returns (Book)
+}
+`
+
+// componentNames returns sorted names of schemas and request bodies, request bodies are prefixed by "body:".
+func componentNames(c *openapi3.Components) string {
+	var names []string
+	for name := range c.Schemas {
+		names = append(names, name)
+	}
+	for name := range c.RequestBodies {
+		names = append(names, "body:"+name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+func TestGeneratePrune(t *testing.T) {
+	api := parseApi(t, pruneApi)
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"unreferenced types", Options{}, "Author,Book"},
+		{"all types", Options{AllTypes: true}, "Author,Book,Filter,SearchRequest,Unused"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, _, err := Generate(api, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := componentNames(doc.Components); got != tt.want {
+				t.Errorf("components = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPruneComponents(t *testing.T) {
+	const spec = `
+openapi: 3.0.3
+info: {title: books, version: v1}
+paths:
+  /books:
+    get:
+      parameters: [{$ref: "#/components/parameters/Page"}]
+      responses:
+        "200": {$ref: "#/components/responses/Books"}
+components:
+  securitySchemes:
+    jwt: {type: http, scheme: bearer}
+  parameters:
+    Page: {name: page, in: query, schema: {$ref: "#/components/schemas/Page"}}
+    Unused: {name: unused, in: query, schema: {type: string}}
+  responses:
+    Books:
+      description: ok
+      content:
+        application/json:
+          schema: {type: array, items: {$ref: "#/components/schemas/Book"}}
+  requestBodies:
+    Orphan:
+      content:
+        application/json:
+          schema: {$ref: "#/components/schemas/Orphan"}
+  schemas:
+    Page: {type: integer}
+    Author:
+      type: object
+      properties: {books: {type: array, items: {$ref: "#/components/schemas/Book"}}}
+    Book:
+      type: object
+      properties: {author: {$ref: "#/components/schemas/Author"}}
+    Orphan: {type: object}
+    Extra:
+      type: object
+      properties: {page: {$ref: "#/components/schemas/Page"}, nested: {$ref: "#/components/schemas/Nested"}}
+    Nested: {type: object}
+`
+	tests := []struct {
+		name  string
+		roots []string
+		want  string
+	}{
+		{"reachable from paths", nil, "Author,Book,Page"},
+		{"roots", []string{"#/components/schemas/Extra"}, "Author,Book,Extra,Nested,Page"},
+		{"root of request body", []string{"#/components/requestBodies/Orphan"}, "Author,Book,Orphan,Page,body:Orphan"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+			if err != nil {
+				t.Fatal(err)
+			}
+			PruneComponents(doc, tt.roots...)
+			if got := componentNames(doc.Components); got != tt.want {
+				t.Errorf("components = %s, want %s", got, tt.want)
+			}
+			if _, ok := doc.Components.Parameters["Page"]; !ok || len(doc.Components.Parameters) != 1 {
+				t.Errorf("parameters = %v, want Page", doc.Components.Parameters)
+			}
+			if _, ok := doc.Components.Responses["Books"]; !ok {
+				t.Errorf("response Books is removed")
+			}
+			if _, ok := doc.Components.SecuritySchemes["jwt"]; !ok {
+				t.Errorf("security scheme jwt is removed")
+			}
+		})
+	}
+}
@@ -22,13 +22,15 @@ type (
 	requestParser struct {
 		rawCache map[string]rawParsedRequest
 		cache    map[string]parsedRequest
+		diags    *diagnostics
 	}
 )
 
-func newRequestParser() requestParser {
+func newRequestParser(diags *diagnostics) requestParser {
 	return requestParser{
 		rawCache: make(map[string]rawParsedRequest),
 		cache:    make(map[string]parsedRequest),
+		diags:    diags,
 	}
 }
 
@@ -65,13 +67,16 @@ func (rp requestParser) parse(
 		}
 
 		fn := getFieldName(member)
-		ms, err := getMemberSchema(member, types, schemas)
+		loc := fmt.Sprintf("%s.%s", typ.Name(), member.Name)
+		ms, err := getMemberSchema(member, types, schemas, rp.diags)
 		if err != nil {
-			fmt.Printf("invalid type of %s.%s\n", typ.Name(), member.Name)
+			rp.diags.add(loc, "invalid type \"%s\": %s", member.Type.Name(), err)
+			continue
 		}
 
 		in := getParameterLocation(member.Tags())
-		required, allowEmpty := parseTags(ms, member.Tags())
+		required, allowEmpty := parseTags(ms, member.Tags(), loc, rp.diags)
+		ms = fillExample(ms, member.Tags(), schemas, loc, rp.diags)
 		if in == "" {
 			localBodySchema.Properties[fn] = ms
 			if required {
@@ -169,6 +174,9 @@ func (rp requestParser) Parse(
 		for i, p := range rpr.params {
 			if p.Value.In == openapi3.ParameterInQuery {
 				schema.Properties[p.Value.Name] = p.Value.Schema
+				if p.Value.Required {
+					schema.Required = append(schema.Required, p.Value.Name)
+				}
 				// if a param both exists in query and form, any one is not required
 				if p.Value.Required {
 					value := *p.Value
@@ -2,22 +2,34 @@ package oas3
 
 import (
 	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
 )
 
-func parseResponse(typ string, types map[string]spec.DefineStruct, responses openapi3.ResponseBodies, schemas openapi3.Schemas) *openapi3.ResponseRef {
+func parseResponse(
+	typ string,
+	envelope *EnvelopeOptions, // wrap response body if not nil
+	types map[string]spec.DefineStruct, // all defined types from api spec
+	responses openapi3.ResponseBodies, // response body references
+	schemas openapi3.Schemas, // schema references
+	diags *diagnostics, // problems found in api spec
+) *openapi3.ResponseRef {
 	if _, ok := responses[typ]; ok {
 		return &openapi3.ResponseRef{
 			Ref: fmt.Sprintf("#/components/responses/%s", typ),
 		}
 	}
-	schema, err := getSchema(typ, types, schemas)
+	schema, err := getSchema(typ, types, schemas, diags)
 	if err != nil {
-		fmt.Printf("GetSchema of \"%s\": %s", typ, err)
+		diags.add("", "invalid response type \"%s\": %s", typ, err)
 		return nil
 	}
+	if envelope != nil {
+		schema = wrapEnvelope(envelope, schema)
+	}
 
 	response := &openapi3.ResponseRef{
 		Value: &openapi3.Response{
@@ -39,3 +51,69 @@ func parseResponse(typ string, types map[string]spec.DefineStruct, responses ope
 		Ref: fmt.Sprintf("#/components/responses/%s", typ),
 	}
 }
+
+// wrapEnvelope wraps data schema with envelope, data property is omitted if data is nil.
+func wrapEnvelope(envelope *EnvelopeOptions, data *openapi3.SchemaRef) *openapi3.SchemaRef {
+	code, message, dataField := envelope.fields()
+	schema := &openapi3.Schema{
+		Type: openapi3.TypeObject,
+		Properties: openapi3.Schemas{
+			code:    openapi3.NewInt32Schema().NewRef(),
+			message: openapi3.NewStringSchema().NewRef(),
+		},
+		Required: []string{code, message},
+	}
+	if data != nil {
+		schema.Properties[dataField] = data
+		schema.Required = append(schema.Required, dataField)
+	}
+	return schema.NewRef()
+}
+
+// parseErrorResponses registers error responses in components, returns references keyed by status code.
+func parseErrorResponses(
+	errorResponses map[int]ErrorResponse,
+	envelope *EnvelopeOptions, // field names of error responses without type, default fields if nil
+	types map[string]spec.DefineStruct, // all defined types from api spec
+	responses openapi3.ResponseBodies, // response body references
+	schemas openapi3.Schemas, // schema references
+	diags *diagnostics, // problems found in api spec
+) map[int]*openapi3.ResponseRef {
+	refs := make(map[int]*openapi3.ResponseRef, len(errorResponses))
+	for code, er := range errorResponses {
+		var (
+			schema *openapi3.SchemaRef
+			err    error
+		)
+		if er.Type == "" {
+			if envelope == nil {
+				envelope = &EnvelopeOptions{}
+			}
+			schema = wrapEnvelope(envelope, nil)
+		} else {
+			schema, err = getSchema(er.Type, types, schemas, diags)
+			if err != nil {
+				diags.add("", "invalid type \"%s\" of error response %d: %s", er.Type, code, err)
+				continue
+			}
+		}
+
+		desc := er.Description
+		if desc == "" {
+			desc = http.StatusText(code)
+		}
+		name := strings.ReplaceAll(http.StatusText(code), " ", "")
+		if name == "" {
+			name = fmt.Sprintf("Status%d", code)
+		}
+		responses[name] = &openapi3.ResponseRef{
+			Value: openapi3.NewResponse().
+				WithDescription(desc).
+				WithContent(openapi3.NewContentWithJSONSchemaRef(schema)),
+		}
+		refs[code] = &openapi3.ResponseRef{
+			Ref: fmt.Sprintf("#/components/responses/%s", name),
+		}
+	}
+	return refs
+}
@@ -11,14 +11,14 @@ import (
 	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
 )
 
-func getSchema(typ string, types map[string]spec.DefineStruct, schemas openapi3.Schemas) (*openapi3.SchemaRef, error) {
+func getSchema(typ string, types map[string]spec.DefineStruct, schemas openapi3.Schemas, diags *diagnostics) (*openapi3.SchemaRef, error) {
 	// map[[2]string]map[string][2]*Bar
 	if strings.HasPrefix(typ, "map[") {
 		valueType, err := GetMapValueType(typ)
 		if err != nil {
 			return nil, errors.WithMessagef(err, "TrimMapTypePrefix with type \"%s\"", typ)
 		}
-		valueSchema, err := getSchema(valueType, types, schemas)
+		valueSchema, err := getSchema(valueType, types, schemas, diags)
 		if err != nil {
 			return nil, err
 		}
@@ -33,7 +33,7 @@ func getSchema(typ string, types map[string]spec.DefineStruct, schemas openapi3.
 			},
 		}, nil
 	} else if strings.HasPrefix(typ, "[]") {
-		itemSchema, err := getSchema(typ[2:], types, schemas)
+		itemSchema, err := getSchema(typ[2:], types, schemas, diags)
 		if err != nil {
 			return nil, err
 		}
@@ -58,7 +58,7 @@ func getSchema(typ string, types map[string]spec.DefineStruct, schemas openapi3.
 		if err != nil {
 			return nil, errors.WithMessagef(err, "parse array \"%s\" dimension", typ)
 		}
-		itemSchema, err := getSchema(typ[i+1:], types, schemas)
+		itemSchema, err := getSchema(typ[i+1:], types, schemas, diags)
 		if err != nil {
 			return nil, err
 		}
@@ -71,15 +71,16 @@ func getSchema(typ string, types map[string]spec.DefineStruct, schemas openapi3.
 			},
 		}, nil
 	} else if typ[0] == '*' {
-		elementSchema, err := getSchema(typ[1:], types, schemas)
+		elementSchema, err := getSchema(typ[1:], types, schemas, diags)
 		if err != nil {
 			return nil, err
 		}
-		// pointer element is a struct type
+		// pointer element is a struct type, nullable is not allowed along with $ref,
+		// wrap the reference by allOf, copying the schema won't terminate for cyclic types.
 		if elementSchema.Value == nil {
-			// make a copy of original schema
-			originalSchema := *schemas[typ[1:]]
-			elementSchema = &originalSchema
+			elementSchema = &openapi3.SchemaRef{
+				Value: &openapi3.Schema{AllOf: openapi3.SchemaRefs{elementSchema}},
+			}
 		}
 		elementSchema.Value.Nullable = true
 		return elementSchema, nil
@@ -117,7 +118,7 @@ func getSchema(typ string, types map[string]spec.DefineStruct, schemas openapi3.
 		openapiFormat = constant.FormatBinary
 	default:
 		if ds, ok := types[typ]; ok {
-			return getStructSchema(ds, types, schemas), nil
+			return getStructSchema(ds, types, schemas, diags), nil
 		}
 		return nil, ErrInvalidType
 	}
@@ -129,7 +130,7 @@ func getSchema(typ string, types map[string]spec.DefineStruct, schemas openapi3.
 	}, nil
 }
 
-func getStructSchema(typ spec.DefineStruct, types map[string]spec.DefineStruct, schemas openapi3.Schemas) *openapi3.SchemaRef {
+func getStructSchema(typ spec.DefineStruct, types map[string]spec.DefineStruct, schemas openapi3.Schemas, diags *diagnostics) *openapi3.SchemaRef {
 	if _, ok := schemas[typ.Name()]; ok {
 		return &openapi3.SchemaRef{Ref: fmt.Sprintf("#/components/schemas/%s", typ.Name())}
 	}
@@ -149,30 +150,32 @@ func getStructSchema(typ spec.DefineStruct, types map[string]spec.DefineStruct,
 	schemas[typ.Name()] = schema
 	for _, m := range typ.Members {
 		fn := getJsonFieldName(m)
+		loc := fmt.Sprintf("%s.%s", typ.Name(), m.Name)
 		// is member a struct type?
 		if mt, ok := m.Type.(spec.DefineStruct); ok {
 			// embedded struct, recursive parse
 			// currently go-zero does not support show members of nested struct over 2 levels(include).
 			// we can get original type from type definitions in this case.
 			mt = types[mt.Name()]
-			ms := getStructSchema(mt, types, schemas)
+			ms := getStructSchema(mt, types, schemas, diags)
 			if m.Name == "" {
 				embeddedSchemas = append(embeddedSchemas, schemas[mt.Name()])
 				continue
 			}
 			schema.Value.Properties[fn] = ms
 		} else {
-			memberSchema, err := getMemberSchema(m, types, schemas)
+			memberSchema, err := getMemberSchema(m, types, schemas, diags)
 			if err != nil {
-				fmt.Printf("invalid type of %s.%s\n", typ.Name(), m.Name)
+				diags.add(loc, "invalid type \"%s\": %s", m.Type.Name(), err)
 				continue
 			}
 			schema.Value.Properties[fn] = memberSchema
 		}
 
-		if required, _ := parseTags(schema.Value.Properties[fn], m.Tags()); required {
+		if required, _ := parseTags(schema.Value.Properties[fn], m.Tags(), loc, diags); required {
 			schema.Value.Required = append(schema.Value.Required, fn)
 		}
+		schema.Value.Properties[fn] = fillExample(schema.Value.Properties[fn], m.Tags(), schemas, loc, diags)
 	}
 
 	for _, embeddedSchema := range embeddedSchemas {
@@ -184,8 +187,8 @@ func getStructSchema(typ spec.DefineStruct, types map[string]spec.DefineStruct,
 	return &openapi3.SchemaRef{Ref: fmt.Sprintf("#/components/schemas/%s", typ.Name())}
 }
 
-func getMemberSchema(m spec.Member, types map[string]spec.DefineStruct, schemas openapi3.Schemas) (*openapi3.SchemaRef, error) {
-	schema, err := getSchema(m.Type.Name(), types, schemas)
+func getMemberSchema(m spec.Member, types map[string]spec.DefineStruct, schemas openapi3.Schemas, diags *diagnostics) (*openapi3.SchemaRef, error) {
+	schema, err := getSchema(m.Type.Name(), types, schemas, diags)
 	if err != nil {
 		return nil, err
 	}
@@ -206,10 +209,10 @@ func getMemberSchema(m spec.Member, types map[string]spec.DefineStruct, schemas
 		if desc != "" || (deprecated != originalSchema.Value.Deprecated) {
 			// make a copy, because description or deprecated will be changed,
 			// we don't want to affect original struct schema definition.
-			originalSchemaCopy := *originalSchema
-			originalSchemaCopy.Value.Description = desc
-			originalSchemaCopy.Value.Deprecated = deprecated
-			schema = &originalSchemaCopy
+			originalValue := *originalSchema.Value
+			originalValue.Description = desc
+			originalValue.Deprecated = deprecated
+			schema = &openapi3.SchemaRef{Value: &originalValue}
 		}
 	} else {
 		schema.Value.Description = desc
@@ -218,14 +221,15 @@ func getMemberSchema(m spec.Member, types map[string]spec.DefineStruct, schemas
 	return schema, nil
 }
 
-func parseTags(s *openapi3.SchemaRef, tags []*spec.Tag) (bool, bool) {
+// parseTags fills schema from tags of member at loc, returns whether the member is required and allows empty value.
+func parseTags(s *openapi3.SchemaRef, tags []*spec.Tag, loc string, diags *diagnostics) (bool, bool) {
 	required := true
 	allowEmpty := true
 
 	for _, tag := range tags {
 		switch tag.Key {
 		case constant.TagKeyForm, constant.TagKeyJson:
-			for _, opt := range tag.Options {
+			for _, opt := range joinBracketOptions(tag.Options) {
 				if s.Value == nil {
 					if opt == constant.OptionOptional || opt == constant.OptionOmitempty ||
 						strings.HasPrefix(opt, constant.OptionDefault) {
@@ -233,20 +237,24 @@ func parseTags(s *openapi3.SchemaRef, tags []*spec.Tag) (bool, bool) {
 					}
 					continue
 				}
+				var err error
 				if opt == constant.OptionOptional || opt == constant.OptionOmitempty {
 					required = false
 				} else if strings.HasPrefix(opt, constant.OptionDefault) {
 					required = false
-					fillDefault(s, opt[len(constant.OptionDefault)+1:])
+					err = fillDefault(s, opt[len(constant.OptionDefault)+1:])
 				} else if strings.HasPrefix(opt, constant.OptionOptions) {
-					fillEnumFromOptions(s, opt[len(constant.OptionOptions)+1:])
+					err = fillEnumFromOptions(s, opt[len(constant.OptionOptions)+1:])
 				} else if strings.HasPrefix(opt, constant.OptionRange) {
-					fillMinMaxFromRange(s, opt[len(constant.OptionRange)+1:])
+					err = fillMinMaxFromRange(s, opt[len(constant.OptionRange)+1:])
+				}
+				if err != nil {
+					diags.add(loc, "invalid tag option \"%s\": %s", opt, err)
 				}
 			}
 		case constant.TagKeyValidate:
 			if validateContainOr(tag) {
-				fmt.Println("currently parsing validate tag which contains \"|\" is not supported")
+				diags.add(loc, "validate tag which contains \"|\" is not supported")
 				continue
 			}
 			var (
@@ -287,13 +295,13 @@ func parseTags(s *openapi3.SchemaRef, tags []*spec.Tag) (bool, bool) {
 						s = s.Value.Items
 					} else if s.Value.Type == openapi3.TypeObject {
 						if s.Value.AdditionalProperties.Schema == nil {
-							fmt.Printf("invalid validate tag \"dive\" for non map type \"%s\"\n", s.Value.Title)
+							diags.add(loc, "invalid validate tag \"dive\" for non map type")
 							return required, allowEmpty
 						}
 						s = s.Value.AdditionalProperties.Schema
 					}
-				} else {
-					parseValidateOption(s, opt)
+				} else if err := parseValidateOption(s, opt); err != nil {
+					diags.add(loc, "invalid validate tag \"%s\": %s", opt, err)
 				}
 			}
 		}
@@ -301,9 +309,26 @@ func parseTags(s *openapi3.SchemaRef, tags []*spec.Tag) (bool, bool) {
 	return required, allowEmpty
 }
 
-// checkDeprecated check Deprecated: comment
+// joinBracketOptions joins options like "options=[foo,bar]" which are split by comma.
+func joinBracketOptions(opts []string) []string {
+	joined := make([]string, 0, len(opts))
+	for i := 0; i < len(opts); i++ {
+		opt := opts[i]
+		if strings.Contains(opt, "=[") || strings.Contains(opt, "=(") {
+			for !strings.HasSuffix(opt, "]") && !strings.HasSuffix(opt, ")") && i+1 < len(opts) {
+				i++
+				opt += "," + opts[i]
+			}
+		}
+		joined = append(joined, opt)
+	}
+	return joined
+}
+
+// checkDeprecated check Deprecated: comment, docs may contain comment markers like "// Deprecated: ...".
 func checkDeprecated(docs spec.Doc) bool {
 	for _, doc := range docs {
+		doc = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(doc), "//"), "/*"))
 		if strings.HasPrefix(doc, "Deprecated:") {
 			return true
 		}
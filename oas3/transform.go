@@ -0,0 +1,38 @@
+package oas3
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+)
+
+type (
+	// Transformer customizes the generated document, it's called for every generated operation
+	// after paths are filled, before unreachable components are pruned.
+	Transformer interface {
+		Transform(doc *openapi3.T, op *OperationContext) error
+	}
+
+	// TransformerFunc is a function used as Transformer.
+	TransformerFunc func(doc *openapi3.T, op *OperationContext) error
+
+	// OperationContext is a generated operation along with the go-zero route it's generated from.
+	OperationContext struct {
+		Group spec.Group
+		Route spec.Route
+		// Path is the route path in openapi format, e.g. "/book/{id}".
+		Path string
+		// Method is the upper case http method.
+		Method string
+		// Operation is the generated operation, its extensions are not nil.
+		Operation *openapi3.Operation
+	}
+)
+
+func (f TransformerFunc) Transform(doc *openapi3.T, op *OperationContext) error {
+	return f(doc, op)
+}
+
+// GroupName returns group name from group annotation of @server.
+func (oc *OperationContext) GroupName() string {
+	return oc.Group.GetAnnotation("group")
+}
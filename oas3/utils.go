@@ -2,9 +2,11 @@ package oas3
 
 import (
 	"fmt"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/jayvynl/goctl-openapi/constant"
@@ -19,6 +21,91 @@ func ConvertPath(path string) string {
 	return pathParamRe.ReplaceAllString(path, `/{$1}`)
 }
 
+// MatchPath reports whether route path matches the glob pattern, see path.Match,
+// additionally a trailing "/**" matches the prefix and any sub path.
+func MatchPath(pattern, routePath string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		if prefix == "" {
+			prefix = "/"
+		}
+		for p := routePath; ; p = path.Dir(p) {
+			if ok, _ := path.Match(prefix, p); ok {
+				return true
+			}
+			if p == "/" || p == "." {
+				return false
+			}
+		}
+	}
+	ok, _ := path.Match(pattern, routePath)
+	return ok
+}
+
+// ConvertName converts name to naming style "camel", "pascal", "snake" or "kebab",
+// name is returned unchanged for other styles.
+func ConvertName(style, name string) string {
+	var words []string
+	switch style {
+	case "camel", "pascal", "snake", "kebab":
+		words = splitWords(name)
+	default:
+		return name
+	}
+
+	for i, w := range words {
+		switch style {
+		case "camel", "pascal":
+			w = strings.ToLower(w)
+			if i > 0 || style == "pascal" {
+				w = strings.ToUpper(w[:1]) + w[1:]
+			}
+		default:
+			w = strings.ToLower(w)
+		}
+		words[i] = w
+	}
+
+	switch style {
+	case "snake":
+		return strings.Join(words, "_")
+	case "kebab":
+		return strings.Join(words, "-")
+	default:
+		return strings.Join(words, "")
+	}
+}
+
+// splitWords splits name like "getHTTPResponse_v2" into ["get", "HTTP", "Response", "v2"].
+func splitWords(name string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(word) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
+
 func GetProperty(properties map[string]string, key string) string {
 	v, err := strconv.Unquote(properties[key])
 	if err == nil {
@@ -0,0 +1,69 @@
+package oas3
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/book/{id}", "/book/{id}", true},
+		{"/book/{id}", "/book/{name}", false},
+		{"/book/*", "/book/{id}", true},
+		{"/book/*", "/book", false},
+		{"/book/*", "/book/{id}/author", false},
+		{"/book/**", "/book", true},
+		{"/book/**", "/book/{id}", true},
+		{"/book/**", "/book/{id}/author", true},
+		{"/book/**", "/books", false},
+		{"/book/**", "/admin/book", false},
+		{"/*/book/**", "/admin/book/{id}", true},
+		{"/**", "/ping", true},
+		{"/[", "/[", false},
+	}
+	for _, tt := range tests {
+		if got := MatchPath(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("MatchPath(%s, %s) = %t, want %t", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestConvertName(t *testing.T) {
+	tests := []struct {
+		style string
+		name  string
+		want  string
+	}{
+		{"camel", "GetBook", "getBook"},
+		{"camel", "get_book_v2", "getBookV2"},
+		{"pascal", "getHTTPResponse", "GetHttpResponse"},
+		{"snake", "GetHTTPResponse_v2", "get_http_response_v2"},
+		{"kebab", "getBookByID", "get-book-by-id"},
+		{"kebab", "Book2Author", "book2-author"},
+		{"", "GetBook", "GetBook"},
+		{"upper", "GetBook", "GetBook"},
+		{"snake", "", ""},
+	}
+	for _, tt := range tests {
+		if got := ConvertName(tt.style, tt.name); got != tt.want {
+			t.Errorf("ConvertName(%s, %s) = %s, want %s", tt.style, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	for name, want := range map[string]string{
+		"getHTTPResponse_v2": "get HTTP Response v2",
+		"HTTP":               "HTTP",
+		"book-list.v1":       "book list v1",
+		"__":                 "",
+	} {
+		if got := strings.Join(splitWords(name), " "); got != want {
+			t.Errorf("splitWords(%s) = %s, want %s", name, got, want)
+		}
+	}
+}
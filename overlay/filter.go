@@ -0,0 +1,268 @@
+package overlay
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+type (
+	// expr is a filter expression.
+	expr interface {
+		// value returns the value of expression and whether it exists.
+		value(current, root interface{}) (interface{}, bool)
+		// test returns the logical result of expression.
+		test(current, root interface{}) bool
+	}
+
+	pathExpr struct {
+		relative bool
+		path     path
+	}
+
+	literalExpr struct {
+		v interface{}
+	}
+
+	compareExpr struct {
+		op          string
+		left, right expr
+	}
+
+	notExpr struct {
+		e expr
+	}
+
+	logicalExpr struct {
+		and         bool
+		left, right expr
+	}
+)
+
+func (e pathExpr) nodes(current, root interface{}) []node {
+	start := root
+	if e.relative {
+		start = current
+	}
+	return e.path.queryNodes([]node{{value: start}}, root)
+}
+
+func (e pathExpr) value(current, root interface{}) (interface{}, bool) {
+	nodes := e.nodes(current, root)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+	return nodes[0].value, true
+}
+
+func (e pathExpr) test(current, root interface{}) bool {
+	return len(e.nodes(current, root)) > 0
+}
+
+func (e literalExpr) value(_, _ interface{}) (interface{}, bool) {
+	return e.v, true
+}
+
+func (e literalExpr) test(_, _ interface{}) bool {
+	b, ok := e.v.(bool)
+	return ok && b
+}
+
+func (e compareExpr) value(current, root interface{}) (interface{}, bool) {
+	return e.test(current, root), true
+}
+
+func (e compareExpr) test(current, root interface{}) bool {
+	l, lok := e.left.value(current, root)
+	r, rok := e.right.value(current, root)
+	switch e.op {
+	case "==":
+		return lok == rok && (!lok || reflect.DeepEqual(l, r))
+	case "!=":
+		return lok != rok || (lok && !reflect.DeepEqual(l, r))
+	}
+	if !lok || !rok {
+		return false
+	}
+
+	var c int
+	switch lv := l.(type) {
+	case float64:
+		rv, ok := r.(float64)
+		if !ok {
+			return false
+		}
+		if lv < rv {
+			c = -1
+		} else if lv > rv {
+			c = 1
+		}
+	case string:
+		rv, ok := r.(string)
+		if !ok {
+			return false
+		}
+		if lv < rv {
+			c = -1
+		} else if lv > rv {
+			c = 1
+		}
+	default:
+		return false
+	}
+	switch e.op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+func (e notExpr) value(current, root interface{}) (interface{}, bool) {
+	return e.test(current, root), true
+}
+
+func (e notExpr) test(current, root interface{}) bool {
+	return !e.e.test(current, root)
+}
+
+func (e logicalExpr) value(current, root interface{}) (interface{}, bool) {
+	return e.test(current, root), true
+}
+
+func (e logicalExpr) test(current, root interface{}) bool {
+	if e.and {
+		return e.left.test(current, root) && e.right.test(current, root)
+	}
+	return e.left.test(current, root) || e.right.test(current, root)
+}
+
+func (p *pathParser) orExpr() (expr, error) {
+	left, err := p.andExpr()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.consume("||") {
+			return left, nil
+		}
+		right, err := p.andExpr()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr{left: left, right: right}
+	}
+}
+
+func (p *pathParser) andExpr() (expr, error) {
+	left, err := p.unaryExpr()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.consume("&&") {
+			return left, nil
+		}
+		right, err := p.unaryExpr()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr{and: true, left: left, right: right}
+	}
+}
+
+func (p *pathParser) unaryExpr() (expr, error) {
+	p.skipSpace()
+	if p.consume("!") && !p.peek("=") {
+		e, err := p.unaryExpr()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{e: e}, nil
+	}
+	if p.consume("(") {
+		e, err := p.orExpr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !p.consume(")") {
+			return nil, p.errorf("\")\" expected")
+		}
+		return e, nil
+	}
+
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			right, err := p.operand()
+			if err != nil {
+				return nil, err
+			}
+			return compareExpr{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *pathParser) operand() (expr, error) {
+	p.skipSpace()
+	switch {
+	case p.consume("@"):
+		segments, err := p.segments(true)
+		if err != nil {
+			return nil, err
+		}
+		return pathExpr{relative: true, path: segments}, nil
+	case p.consume("$"):
+		segments, err := p.segments(true)
+		if err != nil {
+			return nil, err
+		}
+		return pathExpr{path: segments}, nil
+	case p.peek("'") || p.peek(`"`):
+		s, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		return literalExpr{v: s}, nil
+	case p.consume("true"):
+		return literalExpr{v: true}, nil
+	case p.consume("false"):
+		return literalExpr{v: false}, nil
+	case p.consume("null"):
+		return literalExpr{v: nil}, nil
+	}
+
+	start := p.i
+	for p.i < len(p.s) && (p.s[p.i] == '-' || p.s[p.i] == '+' || p.s[p.i] == '.' ||
+		p.s[p.i] == 'e' || p.s[p.i] == 'E' || (p.s[p.i] >= '0' && p.s[p.i] <= '9')) {
+		p.i++
+	}
+	v, err := strconv.ParseFloat(p.s[start:p.i], 64)
+	if err != nil {
+		p.i = start
+		return nil, p.errorf("operand expected")
+	}
+	return literalExpr{v: v}, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
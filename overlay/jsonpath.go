@@ -0,0 +1,300 @@
+package overlay
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A subset of JSONPath (RFC 9535) used to select overlay targets, supported syntax:
+//
+//	$                   root
+//	.name ['name']      child member
+//	.* [*]              wildcard
+//	[0] [-1]            array index
+//	['a','b'] [0,1]     union
+//	..name ..*          descendants
+//	[?(expr)] [?expr]   filter, expr supports @ and $ paths, literals,
+//	                    == != < <= > >=, !, && , || and parentheses
+
+type (
+	path []segment
+
+	segment struct {
+		descendant bool
+		selectors  []selector
+	}
+
+	selector struct {
+		kind   selectorKind
+		name   string
+		index  int
+		filter expr
+	}
+
+	selectorKind int
+
+	// node is a matched value with its location in the document.
+	node struct {
+		value  interface{}
+		parent interface{} // map[string]interface{} or []interface{}, nil for root
+		key    interface{} // string for objects, int for arrays
+	}
+)
+
+const (
+	selectName selectorKind = iota
+	selectWildcard
+	selectIndex
+	selectFilter
+)
+
+func parsePath(s string) (path, error) {
+	p := &pathParser{s: s}
+	if !p.consume("$") {
+		return nil, p.errorf("path must start with \"$\"")
+	}
+	segments, err := p.segments(false)
+	if err != nil {
+		return nil, err
+	}
+	if p.i != len(p.s) {
+		return nil, p.errorf("unexpected character %q", p.s[p.i])
+	}
+	return segments, nil
+}
+
+// query returns nodes selected by path from root.
+func (pt path) query(root interface{}) []node {
+	return pt.queryNodes([]node{{value: root}}, root)
+}
+
+func (pt path) queryNodes(nodes []node, root interface{}) []node {
+	for _, seg := range pt {
+		var next []node
+		for _, n := range nodes {
+			if seg.descendant {
+				for _, d := range descendants(n) {
+					next = append(next, seg.apply(d, root)...)
+				}
+			} else {
+				next = append(next, seg.apply(n, root)...)
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+func (seg segment) apply(n node, root interface{}) []node {
+	var nodes []node
+	for _, sel := range seg.selectors {
+		switch sel.kind {
+		case selectName:
+			if m, ok := n.value.(map[string]interface{}); ok {
+				if v, ok := m[sel.name]; ok {
+					nodes = append(nodes, node{value: v, parent: m, key: sel.name})
+				}
+			}
+		case selectIndex:
+			if a, ok := n.value.([]interface{}); ok {
+				i := sel.index
+				if i < 0 {
+					i += len(a)
+				}
+				if i >= 0 && i < len(a) {
+					nodes = append(nodes, node{value: a[i], parent: a, key: i})
+				}
+			}
+		case selectWildcard, selectFilter:
+			for _, c := range children(n) {
+				if sel.kind == selectWildcard || sel.filter.test(c.value, root) {
+					nodes = append(nodes, c)
+				}
+			}
+		}
+	}
+	return nodes
+}
+
+func children(n node) []node {
+	switch v := n.value.(type) {
+	case map[string]interface{}:
+		keys := sortedKeys(v)
+		nodes := make([]node, len(keys))
+		for i, k := range keys {
+			nodes[i] = node{value: v[k], parent: v, key: k}
+		}
+		return nodes
+	case []interface{}:
+		nodes := make([]node, len(v))
+		for i, e := range v {
+			nodes[i] = node{value: e, parent: v, key: i}
+		}
+		return nodes
+	}
+	return nil
+}
+
+// descendants returns n and all its descendants in document order.
+func descendants(n node) []node {
+	nodes := []node{n}
+	for _, c := range children(n) {
+		nodes = append(nodes, descendants(c)...)
+	}
+	return nodes
+}
+
+type pathParser struct {
+	s string
+	i int
+}
+
+func (p *pathParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid jsonpath \"%s\" at %d: %s", p.s, p.i, fmt.Sprintf(format, args...))
+}
+
+func (p *pathParser) skipSpace() {
+	for p.i < len(p.s) && (p.s[p.i] == ' ' || p.s[p.i] == '\t') {
+		p.i++
+	}
+}
+
+func (p *pathParser) peek(prefix string) bool {
+	return strings.HasPrefix(p.s[p.i:], prefix)
+}
+
+func (p *pathParser) consume(prefix string) bool {
+	if p.peek(prefix) {
+		p.i += len(prefix)
+		return true
+	}
+	return false
+}
+
+// segments parses segments until the path ends, in filter mode stops at first non segment character.
+func (p *pathParser) segments(inFilter bool) (path, error) {
+	var segments path
+	for p.i < len(p.s) {
+		var (
+			seg segment
+			err error
+		)
+		switch {
+		case p.consume(".."):
+			seg.descendant = true
+			if p.peek("[") {
+				seg.selectors, err = p.bracket()
+			} else {
+				seg.selectors, err = p.dotName()
+			}
+		case p.consume("."):
+			seg.selectors, err = p.dotName()
+		case p.peek("["):
+			seg.selectors, err = p.bracket()
+		default:
+			if inFilter {
+				return segments, nil
+			}
+			return nil, p.errorf("unexpected character %q", p.s[p.i])
+		}
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, seg)
+	}
+	return segments, nil
+}
+
+func (p *pathParser) dotName() ([]selector, error) {
+	if p.consume("*") {
+		return []selector{{kind: selectWildcard}}, nil
+	}
+	start := p.i
+	for p.i < len(p.s) && isNameChar(p.s[p.i]) {
+		p.i++
+	}
+	if start == p.i {
+		return nil, p.errorf("member name expected")
+	}
+	return []selector{{kind: selectName, name: p.s[start:p.i]}}, nil
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || c == '-' || c == '$' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func (p *pathParser) bracket() ([]selector, error) {
+	p.consume("[")
+	var selectors []selector
+	for {
+		p.skipSpace()
+		var sel selector
+		switch {
+		case p.consume("*"):
+			sel.kind = selectWildcard
+		case p.consume("?"):
+			p.skipSpace()
+			e, err := p.orExpr()
+			if err != nil {
+				return nil, err
+			}
+			sel.kind = selectFilter
+			sel.filter = e
+		case p.peek("'") || p.peek(`"`):
+			name, err := p.quoted()
+			if err != nil {
+				return nil, err
+			}
+			sel.kind = selectName
+			sel.name = name
+		default:
+			start := p.i
+			if p.peek("-") {
+				p.i++
+			}
+			for p.i < len(p.s) && p.s[p.i] >= '0' && p.s[p.i] <= '9' {
+				p.i++
+			}
+			index, err := strconv.Atoi(p.s[start:p.i])
+			if err != nil {
+				return nil, p.errorf("selector expected")
+			}
+			sel.kind = selectIndex
+			sel.index = index
+		}
+		selectors = append(selectors, sel)
+
+		p.skipSpace()
+		if p.consume("]") {
+			return selectors, nil
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("\"]\" expected")
+		}
+	}
+}
+
+func (p *pathParser) quoted() (string, error) {
+	quote := p.s[p.i]
+	p.i++
+	var sb strings.Builder
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		p.i++
+		switch c {
+		case quote:
+			return sb.String(), nil
+		case '\\':
+			if p.i == len(p.s) {
+				return "", p.errorf("unterminated string")
+			}
+			sb.WriteByte(p.s[p.i])
+			p.i++
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
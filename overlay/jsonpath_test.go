@@ -0,0 +1,114 @@
+package overlay
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const store = `{
+  "store": {
+    "book": [
+      {"title": "Go", "price": 10, "tags": ["lang"]},
+      {"title": "Rust", "price": 20, "isbn": "123"},
+      {"title": "Zig", "price": 30, "draft": true}
+    ],
+    "bicycle": {"color": "red", "price": 15}
+  },
+  "limit": 20
+}`
+
+func TestQuery(t *testing.T) {
+	var root interface{}
+	if err := json.Unmarshal([]byte(store), &root); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path string
+		want string // json array of selected values
+	}{
+		{`$`, `[` + compactJSON(store) + `]`},
+		{`$.limit`, `[20]`},
+		{`$['limit']`, `[20]`},
+		{`$["store"]['bicycle'].color`, `["red"]`},
+		{`$.missing`, `null`},
+		{`$.store.book[0].title`, `["Go"]`},
+		{`$.store.book[-1].title`, `["Zig"]`},
+		{`$.store.book[5]`, `null`},
+		{`$.store.book[0,2].title`, `["Go","Zig"]`},
+		{`$.store.bicycle['color','price']`, `["red",15]`},
+		{`$.store.book[*].price`, `[10,20,30]`},
+		{`$.store.bicycle.*`, `["red",15]`},
+		{`$..price`, `[15,10,20,30]`},
+		{`$.store..title`, `["Go","Rust","Zig"]`},
+		{`$..book[1].title`, `["Rust"]`},
+		{`$..[0]`, `[{"price":10,"tags":["lang"],"title":"Go"},"lang"]`},
+		{`$.store.book[?(@.isbn)].title`, `["Rust"]`},
+		{`$.store.book[?!@.isbn].title`, `["Go","Zig"]`},
+		{`$.store.book[?@.price > 10].title`, `["Rust","Zig"]`},
+		{`$.store.book[?@.price >= 20].title`, `["Rust","Zig"]`},
+		{`$.store.book[?@.price < 20].title`, `["Go"]`},
+		{`$.store.book[?@.price <= 20].title`, `["Go","Rust"]`},
+		{`$.store.book[?@.price == 20].title`, `["Rust"]`},
+		{`$.store.book[?@.price != 20].title`, `["Go","Zig"]`},
+		{`$.store.book[?@.title == 'Go'].price`, `[10]`},
+		{`$.store.book[?@.title > "Go"].title`, `["Rust","Zig"]`},
+		{`$.store.book[?@.price == $.limit].title`, `["Rust"]`},
+		{`$.store.book[?@.draft == true].title`, `["Zig"]`},
+		{`$.store.book[?@.isbn == null].title`, `[]`},
+		{`$.store.book[?@.price > 10 && !@.draft].title`, `["Rust"]`},
+		{`$.store.book[?@.price < 20 || @.draft].title`, `["Go","Zig"]`},
+		{`$.store.book[?(@.price < 20 || @.price > 25) && @.tags].title`, `["Go"]`},
+		{`$.store.book[?@.title > 10].title`, `[]`},
+		{`$.store.book[?@.tags[0] == 'lang'].title`, `["Go"]`},
+		{`$.store.book[?@.price == 1e1].title`, `["Go"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			p, err := parsePath(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			values := make([]interface{}, 0)
+			for _, n := range p.query(root) {
+				values = append(values, n.value)
+			}
+			if len(values) == 0 && tt.want == "null" {
+				return
+			}
+			got, _ := json.Marshal(values)
+			if string(got) != compactJSON(tt.want) {
+				t.Errorf("query = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePathError(t *testing.T) {
+	for _, s := range []string{
+		``,
+		`store`,
+		`$.`,
+		`$..`,
+		`$.store[`,
+		`$.store[0`,
+		`$.store['book`,
+		`$.store[abc]`,
+		`$.store[?(@.a]`,
+		`$.store[?@.a == ]`,
+		`$.store book`,
+		`$.store]`,
+	} {
+		if _, err := parsePath(s); err == nil {
+			t.Errorf("parsePath(%q) succeeded", s)
+		}
+	}
+}
+
+func compactJSON(s string) string {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
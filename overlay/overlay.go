@@ -0,0 +1,239 @@
+package overlay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/invopop/yaml"
+	"github.com/pkg/errors"
+)
+
+// https://github.com/OAI/Overlay-Specification/blob/main/versions/1.0.0.md
+type (
+	Overlay struct {
+		Overlay string   `json:"overlay"`
+		Info    Info     `json:"info"`
+		Extends string   `json:"extends,omitempty"`
+		Actions []Action `json:"actions"`
+	}
+
+	Info struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	}
+
+	Action struct {
+		Target      string      `json:"target"`
+		Description string      `json:"description,omitempty"`
+		Update      interface{} `json:"update,omitempty"`
+		Remove      bool        `json:"remove,omitempty"`
+	}
+
+	// Unmatched is an action whose target matched nothing.
+	Unmatched struct {
+		// Index of the action in overlay actions.
+		Index  int
+		Action Action
+	}
+)
+
+func (u Unmatched) String() string {
+	if u.Action.Description != "" {
+		return fmt.Sprintf("action %d (%s): target \"%s\" matched nothing", u.Index, u.Action.Description, u.Action.Target)
+	}
+	return fmt.Sprintf("action %d: target \"%s\" matched nothing", u.Index, u.Action.Target)
+}
+
+// Load reads an overlay document in json or yaml format.
+func Load(filename string) (*Overlay, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse parses an overlay document in json or yaml format.
+func Parse(data []byte) (*Overlay, error) {
+	var o Overlay
+	if err := yaml.Unmarshal(data, &o); err != nil {
+		return nil, errors.WithMessage(err, "parse overlay")
+	}
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// Validate checks required fields of overlay document.
+func (o *Overlay) Validate() error {
+	if !strings.HasPrefix(o.Overlay, "1.0.") {
+		return fmt.Errorf("unsupported overlay version \"%s\"", o.Overlay)
+	}
+	if len(o.Actions) == 0 {
+		return errors.New("overlay must contain at least one action")
+	}
+	for i, action := range o.Actions {
+		if action.Target == "" {
+			return fmt.Errorf("action %d: target is required", i)
+		}
+		if _, err := parsePath(action.Target); err != nil {
+			return fmt.Errorf("action %d: %s", i, err)
+		}
+		if action.Update == nil && !action.Remove {
+			return fmt.Errorf("action %d: one of update or remove is required", i)
+		}
+	}
+	return nil
+}
+
+// Apply applies overlay actions to doc in order, returns actions whose target matched nothing.
+func (o *Overlay) Apply(doc *openapi3.T) ([]Unmatched, error) {
+	docJson, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var root interface{}
+	if err = json.Unmarshal(docJson, &root); err != nil {
+		return nil, err
+	}
+
+	var unmatched []Unmatched
+	for i, action := range o.Actions {
+		target, err := parsePath(action.Target)
+		if err != nil {
+			return nil, fmt.Errorf("action %d: %s", i, err)
+		}
+		nodes := target.query(root)
+		if len(nodes) == 0 {
+			unmatched = append(unmatched, Unmatched{Index: i, Action: action})
+			continue
+		}
+
+		if action.Remove {
+			for _, n := range nodes {
+				if n.parent == nil {
+					return nil, fmt.Errorf("action %d: can't remove document root", i)
+				}
+				remove(n)
+			}
+			root = compact(root)
+			continue
+		}
+
+		// every node gets its own copy, so that later actions updating one node don't change others.
+		for _, n := range nodes {
+			if n.parent == nil {
+				root = update(root, deepCopy(action.Update))
+			} else {
+				set(n, update(n.value, deepCopy(action.Update)))
+			}
+		}
+	}
+
+	updatedJson, err := json.Marshal(root)
+	if err != nil {
+		return nil, err
+	}
+	var updated openapi3.T
+	if err = json.Unmarshal(updatedJson, &updated); err != nil {
+		return nil, errors.WithMessage(err, "load updated document")
+	}
+	*doc = updated
+	return unmatched, nil
+}
+
+// update merges value into target. objects are merged recursively, properties of value replace
+// properties of target with the same name. value is appended to array target, primitive target is replaced.
+func update(target, value interface{}) interface{} {
+	switch t := target.(type) {
+	case map[string]interface{}:
+		v, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		for k, pv := range v {
+			if tv, ok := t[k]; ok {
+				if _, ok := tv.(map[string]interface{}); ok {
+					t[k] = update(tv, pv)
+					continue
+				}
+			}
+			t[k] = pv
+		}
+		return t
+	case []interface{}:
+		if v, ok := value.([]interface{}); ok {
+			return append(t, v...)
+		}
+		return append(t, value)
+	default:
+		return value
+	}
+}
+
+// deepCopy copies objects and arrays decoded from json.
+func deepCopy(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			m[k] = deepCopy(e)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(t))
+		for i, e := range t {
+			l[i] = deepCopy(e)
+		}
+		return l
+	default:
+		return v
+	}
+}
+
+// removed marks array elements to be removed by compact.
+type removed struct{}
+
+func remove(n node) {
+	switch p := n.parent.(type) {
+	case map[string]interface{}:
+		delete(p, n.key.(string))
+	case []interface{}:
+		p[n.key.(int)] = removed{}
+	}
+}
+
+func set(n node, v interface{}) {
+	switch p := n.parent.(type) {
+	case map[string]interface{}:
+		p[n.key.(string)] = v
+	case []interface{}:
+		p[n.key.(int)] = v
+	}
+}
+
+// compact drops array elements marked as removed.
+func compact(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = compact(e)
+		}
+		return t
+	case []interface{}:
+		compacted := make([]interface{}, 0, len(t))
+		for _, e := range t {
+			if _, ok := e.(removed); ok {
+				continue
+			}
+			compacted = append(compacted, compact(e))
+		}
+		return compacted
+	default:
+		return v
+	}
+}
@@ -0,0 +1,185 @@
+package overlay
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const doc = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Books", "version": "v1"},
+  "tags": [{"name": "book"}, {"name": "internal"}],
+  "paths": {
+    "/book": {
+      "get": {"operationId": "ListBook", "tags": ["book"], "responses": {"200": {"description": "ok"}}},
+      "post": {"operationId": "CreateBook", "tags": ["book"], "responses": {"200": {"description": "ok"}}}
+    },
+    "/internal/stats": {
+      "get": {"operationId": "Stats", "tags": ["internal"], "responses": {"200": {"description": "ok"}}}
+    }
+  }
+}`
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name      string
+		overlay   string
+		unmatched []int
+		want      map[string]interface{} // values keyed by space separated path
+	}{
+		{
+			name: "update objects",
+			overlay: `
+overlay: 1.0.0
+info: {title: t, version: v1}
+actions:
+  - target: $.info
+    update: {description: Book service, version: v2}
+`,
+			want: map[string]interface{}{
+				"info description": "Book service",
+				"info version":     "v2",
+				"info title":       "Books",
+			},
+		},
+		{
+			name: "update arrays and primitives",
+			overlay: `
+overlay: 1.0.0
+info: {title: t, version: v1}
+actions:
+  - target: $.tags
+    update: {name: author}
+  - target: $.paths['/book'].get.tags
+    update: [list]
+  - target: $.paths['/book'].post.operationId
+    update: AddBook
+`,
+			want: map[string]interface{}{
+				"tags len":                     3,
+				"paths /book get tags len":     2,
+				"paths /book post operationId": "AddBook",
+			},
+		},
+		{
+			name: "updates are not shared by matched nodes",
+			overlay: `
+overlay: 1.0.0
+info: {title: t, version: v1}
+actions:
+  - target: $.paths.*.*
+    update: {x-rate: {limit: 10}}
+  - target: $.paths['/book'].get.x-rate
+    update: {limit: 5}
+`,
+			want: map[string]interface{}{
+				"paths /book get x-rate limit":           5.0,
+				"paths /book post x-rate limit":          10.0,
+				"paths /internal/stats get x-rate limit": 10.0,
+			},
+		},
+		{
+			name: "remove by filter",
+			overlay: `
+overlay: 1.0.0
+info: {title: t, version: v1}
+actions:
+  - target: $.paths.*[?@.tags[0] == 'internal']
+    remove: true
+  - target: $.tags[?@.name == 'internal']
+    remove: true
+  - target: $.paths.*.delete
+    remove: true
+`,
+			unmatched: []int{2},
+			want: map[string]interface{}{
+				"paths /internal/stats get":   nil,
+				"paths /book get operationId": "ListBook",
+				"tags len":                    1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := Parse([]byte(tt.overlay))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var d openapi3.T
+			if err = json.Unmarshal([]byte(doc), &d); err != nil {
+				t.Fatal(err)
+			}
+			unmatched, err := o.Apply(&d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var indexes []int
+			for _, u := range unmatched {
+				indexes = append(indexes, u.Index)
+			}
+			if !reflect.DeepEqual(indexes, tt.unmatched) {
+				t.Errorf("unmatched = %v, want %v", indexes, tt.unmatched)
+			}
+
+			data, _ := json.Marshal(&d)
+			var result map[string]interface{}
+			_ = json.Unmarshal(data, &result)
+			for p, want := range tt.want {
+				if got := lookup(result, p); !reflect.DeepEqual(got, want) {
+					t.Errorf("%s = %#v, want %#v", p, got, want)
+				}
+			}
+		})
+	}
+}
+
+// lookup returns value at path of object keys, "len" returns length of arrays.
+func lookup(v interface{}, path string) interface{} {
+	for _, k := range strings.Split(path, " ") {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			v = t[k]
+		case []interface{}:
+			if k == "len" {
+				return len(t)
+			}
+			return nil
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+func TestValidate(t *testing.T) {
+	tests := []string{
+		`{"overlay": "2.0.0", "actions": [{"target": "$", "remove": true}]}`,
+		`{"overlay": "1.0.0", "actions": []}`,
+		`{"overlay": "1.0.0", "actions": [{"update": {}}]}`,
+		`{"overlay": "1.0.0", "actions": [{"target": "$.a["}]}`,
+		`{"overlay": "1.0.0", "actions": [{"target": "$.a"}]}`,
+	}
+	for _, tt := range tests {
+		if _, err := Parse([]byte(tt)); err == nil {
+			t.Errorf("overlay %s is valid", tt)
+		}
+	}
+}
+
+func TestApplyRemoveRoot(t *testing.T) {
+	o, err := Parse([]byte(`{"overlay": "1.0.0", "actions": [{"target": "$", "remove": true}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d openapi3.T
+	if err = json.Unmarshal([]byte(doc), &d); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = o.Apply(&d); err == nil {
+		t.Error("document root is removed")
+	}
+}
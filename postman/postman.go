@@ -0,0 +1,316 @@
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jayvynl/goctl-openapi/internal/docutil"
+	"github.com/jayvynl/goctl-openapi/sample"
+)
+
+const (
+	// SchemaURL is the schema of Postman Collection Format v2.1.
+	SchemaURL = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+	// DefaultBaseURL is the value of baseUrl variable if the document has no server.
+	DefaultBaseURL = "http://localhost:8888"
+)
+
+// https://schema.postman.com/collection/json/v2.1.0/draft-07/docs/index.html
+type (
+	Collection struct {
+		Info     Info       `json:"info"`
+		Item     []*Item    `json:"item"`
+		Variable []Variable `json:"variable,omitempty"`
+	}
+
+	Info struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		Version     string `json:"version,omitempty"`
+		Schema      string `json:"schema"`
+	}
+
+	// Item is a folder if it has items, otherwise it's a request.
+	Item struct {
+		Name        string   `json:"name"`
+		Description string   `json:"description,omitempty"`
+		Item        []*Item  `json:"item,omitempty"`
+		Request     *Request `json:"request,omitempty"`
+	}
+
+	Request struct {
+		Method      string     `json:"method"`
+		Header      []KeyValue `json:"header"`
+		URL         URL        `json:"url"`
+		Body        *Body      `json:"body,omitempty"`
+		Auth        *Auth      `json:"auth,omitempty"`
+		Description string     `json:"description,omitempty"`
+	}
+
+	URL struct {
+		Raw      string     `json:"raw"`
+		Host     []string   `json:"host"`
+		Path     []string   `json:"path"`
+		Query    []KeyValue `json:"query,omitempty"`
+		Variable []KeyValue `json:"variable,omitempty"`
+	}
+
+	KeyValue struct {
+		Key         string `json:"key"`
+		Value       string `json:"value"`
+		Type        string `json:"type,omitempty"`
+		Description string `json:"description,omitempty"`
+		Disabled    bool   `json:"disabled,omitempty"`
+	}
+
+	Body struct {
+		Mode       string       `json:"mode"`
+		Raw        string       `json:"raw,omitempty"`
+		URLEncoded []KeyValue   `json:"urlencoded,omitempty"`
+		FormData   []KeyValue   `json:"formdata,omitempty"`
+		Options    *BodyOptions `json:"options,omitempty"`
+	}
+
+	BodyOptions struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	}
+
+	// Auth is bearer, apikey or noauth.
+	Auth struct {
+		Type   string     `json:"type"`
+		Bearer []KeyValue `json:"bearer,omitempty"`
+		APIKey []KeyValue `json:"apikey,omitempty"`
+	}
+
+	Variable struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+		Type  string `json:"type,omitempty"`
+	}
+)
+
+// New converts doc to a collection, requests are put into folders by the first tag of operations.
+// The base url of requests is variable {{baseUrl}}, operations requiring http bearer security use variable {{token}}.
+func New(doc *openapi3.T) *Collection {
+	c := &Collection{Info: Info{Schema: SchemaURL}}
+	if doc.Info != nil {
+		c.Info.Name = doc.Info.Title
+		c.Info.Description = doc.Info.Description
+		c.Info.Version = doc.Info.Version
+	}
+	baseURL := DefaultBaseURL
+	if len(doc.Servers) > 0 {
+		baseURL = strings.TrimSuffix(doc.Servers[0].URL, "/")
+	}
+	c.Variable = []Variable{
+		{Key: "baseUrl", Value: baseURL, Type: "string"},
+		{Key: "token", Value: "", Type: "string"},
+	}
+
+	folders := make(map[string]*Item)
+	paths := doc.Paths.InMatchingOrder()
+	sort.Strings(paths)
+	for _, path := range paths {
+		item := doc.Paths.Value(path)
+		for _, method := range docutil.Methods(item) {
+			op := item.GetOperation(method)
+			tag := docutil.GroupTag(op.Tags)
+			folder, ok := folders[tag]
+			if !ok {
+				folder = &Item{Name: tag, Item: []*Item{}}
+				if t := doc.Tags.Get(tag); t != nil {
+					folder.Description = t.Description
+				}
+				folders[tag] = folder
+				c.Item = append(c.Item, folder)
+			}
+			folder.Item = append(folder.Item, newItem(doc, path, method, item, op))
+		}
+	}
+	sort.Slice(c.Item, func(i, j int) bool {
+		return c.Item[i].Name < c.Item[j].Name
+	})
+	return c
+}
+
+// Marshal returns indented json of collection.
+func (c *Collection) Marshal() ([]byte, error) {
+	return json.MarshalIndent(c, "", "  ")
+}
+
+func newItem(doc *openapi3.T, path, method string, pathItem *openapi3.PathItem, op *openapi3.Operation) *Item {
+	name := op.Summary
+	if name == "" {
+		name = op.OperationID
+	}
+	if name == "" {
+		name = fmt.Sprintf("%s %s", method, path)
+	}
+
+	req := &Request{
+		Method:      method,
+		Header:      []KeyValue{},
+		Description: op.Description,
+		URL:         URL{Host: []string{"{{baseUrl}}"}},
+	}
+
+	// path "/book/{id}" is "/book/:id" in postman.
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segment = ":" + segment[1:len(segment)-1]
+		}
+		req.URL.Path = append(req.URL.Path, segment)
+	}
+
+	params := append(openapi3.Parameters{}, op.Parameters...)
+	for _, ref := range pathItem.Parameters {
+		if p := docutil.ResolveParameter(doc, ref); p != nil && op.Parameters.GetByInAndName(p.In, p.Name) == nil {
+			params = append(params, ref)
+		}
+	}
+	for _, ref := range params {
+		p := docutil.ResolveParameter(doc, ref)
+		if p == nil {
+			continue
+		}
+		kv := KeyValue{
+			Key:         p.Name,
+			Value:       docutil.FormatValue(sample.Value(doc, p.Schema)),
+			Description: p.Description,
+		}
+		switch p.In {
+		case openapi3.ParameterInPath:
+			req.URL.Variable = append(req.URL.Variable, kv)
+		case openapi3.ParameterInQuery:
+			kv.Disabled = !p.Required
+			req.URL.Query = append(req.URL.Query, kv)
+		case openapi3.ParameterInHeader:
+			kv.Disabled = !p.Required
+			req.Header = append(req.Header, kv)
+		}
+	}
+
+	req.URL.Raw = "{{baseUrl}}/" + strings.Join(req.URL.Path, "/")
+	var query []string
+	for _, kv := range req.URL.Query {
+		if !kv.Disabled {
+			query = append(query, kv.Key+"="+kv.Value)
+		}
+	}
+	if len(query) > 0 {
+		req.URL.Raw += "?" + strings.Join(query, "&")
+	}
+
+	if rb := docutil.ResolveRequestBody(doc, op.RequestBody); rb != nil {
+		contentType, body := newBody(doc, rb.Content)
+		if body != nil {
+			req.Body = body
+			if body.Mode != "formdata" {
+				req.Header = append(req.Header, KeyValue{Key: "Content-Type", Value: contentType})
+			}
+		}
+	}
+
+	req.Auth = newAuth(doc, op)
+	return &Item{Name: name, Request: req}
+}
+
+// newBody synthesizes request body of preferred media type, json, urlencoded form, or multipart form.
+func newBody(doc *openapi3.T, content openapi3.Content) (string, *Body) {
+	if mt := content.Get("application/json"); mt != nil {
+		data, err := json.MarshalIndent(sample.Value(doc, mt.Schema), "", "  ")
+		if err != nil {
+			return "", nil
+		}
+		body := &Body{Mode: "raw", Raw: string(data), Options: &BodyOptions{}}
+		body.Options.Raw.Language = "json"
+		return "application/json", body
+	}
+	if mt := content.Get("application/x-www-form-urlencoded"); mt != nil {
+		return "application/x-www-form-urlencoded", &Body{Mode: "urlencoded", URLEncoded: formFields(doc, mt.Schema)}
+	}
+	if mt := content.Get("multipart/form-data"); mt != nil {
+		return "multipart/form-data", &Body{Mode: "formdata", FormData: formFields(doc, mt.Schema)}
+	}
+	return "", nil
+}
+
+func formFields(doc *openapi3.T, ref *openapi3.SchemaRef) []KeyValue {
+	obj, _ := sample.Value(doc, ref).(map[string]interface{})
+	s := docutil.ResolveSchema(doc, ref)
+	required := make(map[string]bool)
+	if s != nil {
+		for _, name := range s.Required {
+			required[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fields := make([]KeyValue, 0, len(names))
+	for _, name := range names {
+		fields = append(fields, KeyValue{
+			Key:      name,
+			Value:    docutil.FormatValue(obj[name]),
+			Type:     "text",
+			Disabled: !required[name],
+		})
+	}
+	return fields
+}
+
+// newAuth returns auth of the first supported security requirement of operation, routes of jwt groups have their own
+// requirements, the global requirement of the document is ignored, because it applies to routes without jwt as well.
+// nil means inheriting from parent, which is no auth for collection.
+func newAuth(doc *openapi3.T, op *openapi3.Operation) *Auth {
+	if op.Security == nil || doc.Components == nil {
+		return nil
+	}
+	for _, requirement := range *op.Security {
+		if auth := requirementAuth(doc, requirement); auth != nil {
+			return auth
+		}
+	}
+	return nil
+}
+
+// requirementAuth returns auth of the first supported security scheme of requirement in name order.
+func requirementAuth(doc *openapi3.T, requirement openapi3.SecurityRequirement) *Auth {
+	names := make([]string, 0, len(requirement))
+	for name := range requirement {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ref, ok := doc.Components.SecuritySchemes[name]
+		if !ok || ref.Value == nil {
+			continue
+		}
+		scheme := ref.Value
+		switch {
+		case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "bearer"):
+			return &Auth{
+				Type:   "bearer",
+				Bearer: []KeyValue{{Key: "token", Value: "{{token}}", Type: "string"}},
+			}
+		case scheme.Type == "apiKey" && (scheme.In == openapi3.ParameterInHeader || scheme.In == openapi3.ParameterInQuery):
+			return &Auth{
+				Type: "apikey",
+				APIKey: []KeyValue{
+					{Key: "key", Value: scheme.Name, Type: "string"},
+					{Key: "value", Value: "{{token}}", Type: "string"},
+					{Key: "in", Value: scheme.In, Type: "string"},
+				},
+			}
+		}
+	}
+	return nil
+}
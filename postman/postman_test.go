@@ -0,0 +1,222 @@
+package postman
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const spec = `
+openapi: 3.0.3
+info: {title: books, version: v1}
+servers: [{url: "https://example.com/v1/"}]
+security: [{jwt: []}]
+tags: [{name: book, description: Books}]
+paths:
+  /books/{id}:
+    parameters:
+      - {name: X-Tenant, in: header, required: true, schema: {type: string, example: acme}}
+    get:
+      tags: [book]
+      summary: Get book
+      security: [{jwt: []}]
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer, example: 7}}
+        - {name: fields, in: query, schema: {type: array, items: {type: string, example: name}}}
+        - {name: lang, in: query, required: true, schema: {type: string, enum: [en]}}
+      responses: {"200": {description: ok}}
+    put:
+      tags: [book]
+      operationId: updateBook
+      security: [{oauth: []}, {apiKey: []}]
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+      requestBody:
+        content:
+          application/json:
+            schema: {type: object, properties: {name: {type: string, example: go}}}
+      responses: {"200": {description: ok}}
+  /login:
+    post:
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              required: [user]
+              properties: {user: {type: string, example: bob}, remember: {type: boolean}}
+      responses: {"200": {description: ok}}
+  /avatar:
+    post:
+      tags: [account]
+      security: []
+      requestBody:
+        content:
+          multipart/form-data:
+            schema: {type: object, properties: {file: {type: string, format: binary}}}
+      responses: {"200": {description: ok}}
+components:
+  securitySchemes:
+    jwt: {type: http, scheme: bearer}
+    apiKey: {type: apiKey, in: header, name: X-Api-Key}
+    oauth: {type: oauth2, flows: {implicit: {authorizationUrl: "https://example.com/auth", scopes: {}}}}
+`
+
+func load(t *testing.T) *Collection {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(doc)
+}
+
+// find returns the request named name.
+func find(t *testing.T, c *Collection, name string) *Request {
+	t.Helper()
+	for _, folder := range c.Item {
+		for _, item := range folder.Item {
+			if item.Name == name {
+				return item.Request
+			}
+		}
+	}
+	t.Fatalf("request %s not found", name)
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	c := load(t)
+
+	var folders []string
+	for _, folder := range c.Item {
+		folders = append(folders, folder.Name)
+	}
+	if got := strings.Join(folders, ","); got != "account,book,default" {
+		t.Errorf("folders = %s, want account,book,default", got)
+	}
+	if c.Item[1].Description != "Books" {
+		t.Errorf("folder description = %q, want Books", c.Item[1].Description)
+	}
+	if c.Info.Name != "books" || c.Variable[0].Value != "https://example.com/v1" {
+		t.Errorf("info = %+v, variables = %+v", c.Info, c.Variable)
+	}
+
+	get := find(t, c, "Get book")
+	tests := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"raw url", get.URL.Raw, "{{baseUrl}}/books/:id?lang=en"},
+		{"path", strings.Join(get.URL.Path, "/"), "books/:id"},
+		{"path variable", get.URL.Variable[0].Value, "7"},
+		{"optional query", get.URL.Query[0].Disabled, true},
+		{"array query", get.URL.Query[0].Value, "name"},
+		{"required query", get.URL.Query[1].Disabled, false},
+		{"path item header", get.Header[0].Key + "=" + get.Header[0].Value, "X-Tenant=acme"},
+		{"no body", get.Body == nil, true},
+		{"operation id name", find(t, c, "updateBook").Method, "PUT"},
+		{"method path name", find(t, c, "POST /login").Method, "POST"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestNewBody(t *testing.T) {
+	c := load(t)
+	tests := []struct {
+		request     string
+		mode        string
+		contentType string
+		fields      string
+	}{
+		{"updateBook", "raw", "application/json", ""},
+		{"POST /login", "urlencoded", "application/x-www-form-urlencoded", "remember=true(disabled),user=bob"},
+		{"POST /avatar", "formdata", "", "file=string(disabled)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.request, func(t *testing.T) {
+			req := find(t, c, tt.request)
+			if req.Body == nil || req.Body.Mode != tt.mode {
+				t.Fatalf("body = %+v, want mode %s", req.Body, tt.mode)
+			}
+			var contentType string
+			for _, h := range req.Header {
+				if h.Key == "Content-Type" {
+					contentType = h.Value
+				}
+			}
+			if contentType != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", contentType, tt.contentType)
+			}
+
+			var fields []string
+			for _, kv := range append(req.Body.URLEncoded, req.Body.FormData...) {
+				field := kv.Key + "=" + kv.Value
+				if kv.Disabled {
+					field += "(disabled)"
+				}
+				fields = append(fields, field)
+			}
+			if got := strings.Join(fields, ","); got != tt.fields {
+				t.Errorf("fields = %s, want %s", got, tt.fields)
+			}
+		})
+	}
+
+	var v map[string]interface{}
+	if err := json.Unmarshal([]byte(find(t, c, "updateBook").Body.Raw), &v); err != nil || v["name"] != "go" {
+		t.Errorf("raw body = %v, %v", v, err)
+	}
+}
+
+func TestNewAuth(t *testing.T) {
+	c := load(t)
+	tests := []struct {
+		request string
+		want    string
+	}{
+		// bearer requirement of the operation.
+		{"Get book", "bearer"},
+		// unsupported oauth requirement is skipped.
+		{"updateBook", "apikey key=X-Api-Key in=header"},
+		// global requirement of the document doesn't apply.
+		{"POST /login", ""},
+		// explicitly unsecured.
+		{"POST /avatar", ""},
+	}
+	for _, tt := range tests {
+		auth := find(t, c, tt.request).Auth
+		var got string
+		if auth != nil {
+			got = auth.Type
+			for _, kv := range auth.APIKey {
+				if kv.Key != "value" {
+					got += " " + kv.Key + "=" + kv.Value
+				}
+			}
+		}
+		if got != tt.want {
+			t.Errorf("auth of %s = %q, want %q", tt.request, got, tt.want)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	data, err := load(t).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v map[string]interface{}
+	if err = json.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+	if v["info"].(map[string]interface{})["schema"] != SchemaURL {
+		t.Errorf("info = %v, want schema %s", v["info"], SchemaURL)
+	}
+}
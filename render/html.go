@@ -0,0 +1,28 @@
+package render
+
+import (
+	"embed"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+//go:embed templates
+var templates embed.FS
+
+var htmlTemplate = template.Must(template.New("reference.html").Funcs(template.FuncMap{
+	"anchor":          anchor,
+	"operationAnchor": operationAnchor,
+	"lower":           strings.ToLower,
+	// indent returns left padding of nested fields in pixels.
+	"indent": func(depth int) int {
+		return 8 + depth*20
+	},
+}).ParseFS(templates, "templates/reference.html"))
+
+// HTML writes a self-contained html reference of doc, it doesn't load any external resource.
+func HTML(w io.Writer, doc *openapi3.T) error {
+	return htmlTemplate.Execute(w, newReference(doc))
+}
@@ -0,0 +1,199 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const spec = `
+openapi: 3.0.3
+info: {title: Book_store, version: v1, description: Books.}
+servers: [{url: "https://example.com/v1"}]
+tags: [{name: book, description: Book operations.}]
+paths:
+  /books/{id}:
+    get:
+      tags: [book]
+      summary: Get *book*
+      operationId: getBook
+      security: [{jwt: []}]
+      parameters:
+        - {name: id, in: path, required: true, description: "book id | isbn", schema: {type: integer, format: int64, minimum: 1}}
+        - {name: X-Tenant, in: header, deprecated: true, schema: {type: string}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Book"}
+        "404": {description: not found}
+  /books:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [title]
+              properties:
+                title: {type: string, minLength: 1, maxLength: 64}
+                meta:
+                  type: object
+                  properties: {pages: {type: integer}}
+      responses: {"204": {description: created}}
+components:
+  securitySchemes:
+    jwt: {type: http, scheme: bearer}
+  schemas:
+    Book:
+      type: object
+      description: A book.
+      required: [title]
+      properties:
+        title: {type: string}
+        author: {nullable: true, allOf: [{$ref: "#/components/schemas/Author"}]}
+        tags: {type: array, items: {type: object, properties: {name: {type: string}}}}
+        status: {type: string, enum: [draft, published], default: draft}
+    Author:
+      type: object
+      deprecated: true
+      properties: {name: {type: string}}
+    Ids: {type: array, items: {type: integer}, minItems: 1, maxItems: 1}
+`
+
+func load(t *testing.T) *openapi3.T {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestTypeName(t *testing.T) {
+	doc := load(t)
+	book := doc.Components.Schemas["Book"].Value
+	inline := func(s *openapi3.Schema) *openapi3.SchemaRef {
+		return openapi3.NewSchemaRef("", s)
+	}
+	tests := []struct {
+		name    string
+		ref     *openapi3.SchemaRef
+		typ     string
+		typeRef string
+	}{
+		{"nil", nil, "any", ""},
+		{"reference", openapi3.NewSchemaRef("#/components/schemas/Book", nil), "Book", "Book"},
+		{"nullable reference", book.Properties["author"], "Author", "Author"},
+		{"format", inline(&openapi3.Schema{Type: "integer", Format: "int64"}), "integer(int64)", ""},
+		{"array of references", inline(&openapi3.Schema{Type: "array", Items: openapi3.NewSchemaRef("#/components/schemas/Author", nil)}), "array[Author]", "Author"},
+		{"map", inline(&openapi3.Schema{Type: "object", AdditionalProperties: openapi3.AdditionalProperties{Schema: openapi3.NewStringSchema().NewRef()}}), "map[string, string]", ""},
+		{"object", inline(openapi3.NewObjectSchema()), "object", ""},
+		{"any", inline(&openapi3.Schema{}), "any", ""},
+		{"one of", inline(&openapi3.Schema{OneOf: openapi3.SchemaRefs{openapi3.NewStringSchema().NewRef(), openapi3.NewSchemaRef("#/components/schemas/Author", nil)}}), "oneOf[string, Author]", ""},
+	}
+	for _, tt := range tests {
+		typ, typeRef := typeName(doc, tt.ref)
+		if typ != tt.typ || typeRef != tt.typeRef {
+			t.Errorf("%s: typeName() = %s, %s, want %s, %s", tt.name, typ, typeRef, tt.typ, tt.typeRef)
+		}
+	}
+}
+
+func TestConstraints(t *testing.T) {
+	one, ten := uint64(1), uint64(10)
+	min, max, step := 0.5, 10.0, 0.5
+	tests := []struct {
+		name   string
+		schema *openapi3.Schema
+		want   string
+	}{
+		{"none", &openapi3.Schema{}, ""},
+		{"nullable default", &openapi3.Schema{Nullable: true, Default: "a"}, `nullable, default: "a"`},
+		{"enum", &openapi3.Schema{Enum: []interface{}{float64(1), "b"}}, `enum: 1, "b"`},
+		{"exclusive range", &openapi3.Schema{Min: &min, ExclusiveMin: true, Max: &max, MultipleOf: &step}, "> 0.5, <= 10, multiple of 0.5"},
+		{"inclusive range", &openapi3.Schema{Min: &min, Max: &max, ExclusiveMax: true}, ">= 0.5, < 10"},
+		{"length", &openapi3.Schema{MinLength: 1, MaxLength: &ten, Pattern: "^a"}, "length >= 1, length <= 10, pattern: ^a"},
+		{"exact items", &openapi3.Schema{MinItems: 1, MaxItems: &one, UniqueItems: true}, "items = 1, unique items"},
+		{"max properties", &openapi3.Schema{MaxProps: &ten}, "properties <= 10"},
+		{"access", &openapi3.Schema{ReadOnly: true, WriteOnly: true}, "read only, write only"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(constraints(tt.schema), ", "); got != tt.want {
+			t.Errorf("%s: constraints() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFields(t *testing.T) {
+	doc := load(t)
+	var got []string
+	for _, f := range fields(doc, doc.Components.Schemas["Book"].Value) {
+		got = append(got, fmt.Sprintf("%s:%d:%s:%t", f.Name, f.Depth, f.Type, f.Required))
+	}
+	want := "author:0:Author:false status:0:string:false tags:0:array[object]:false tags[].name:1:string:false title:0:string:true"
+	if strings.Join(got, " ") != want {
+		t.Errorf("fields() = %s, want %s", strings.Join(got, " "), want)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := HTML(&buf, load(t)); err != nil {
+		t.Fatal(err)
+	}
+	html := buf.String()
+	for _, want := range []string{
+		`<a href="#operation-get--books--id-">`,
+		`<section class="operation" id="operation-get--books--id-">`,
+		`<section class="schema deprecated" id="schema-author">`,
+		`<a class="type" href="#schema-author">Author</a>`,
+		`<h2 class="group" id="tag-default">default</h2>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("output doesn't contain %q", want)
+		}
+	}
+	// the reference is self-contained.
+	for _, external := range []string{`src="http`, `href="http`, "<script src", "<link"} {
+		if strings.Contains(html, external) {
+			t.Errorf("output loads external resource %q", external)
+		}
+	}
+}
+
+func TestAnchor(t *testing.T) {
+	for name, want := range map[string]string{
+		"Book":            "schema-book",
+		"Book_v1":         "schema-book_v1",
+		"GET /books/{id}": "schema-get--books--id-",
+		"书":               "schema--",
+	} {
+		if got := anchor("schema", name); got != want {
+			t.Errorf("anchor(%q) = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestGroups(t *testing.T) {
+	doc := load(t)
+	doc.Paths.Value("/books/{id}").Get.Tags = []string{"book", "admin"}
+	var got []string
+	for _, g := range newReference(doc).Groups {
+		var ops []string
+		for _, op := range g.Operations {
+			ops = append(ops, op.Method+" "+op.Path)
+		}
+		got = append(got, fmt.Sprintf("%s(%s): %s", g.Tag, g.Description, strings.Join(ops, ", ")))
+	}
+	// operations are listed under their first tag only.
+	want := "book(Book operations.): GET /books/{id}; default(): POST /books"
+	if strings.Join(got, "; ") != want {
+		t.Errorf("groups = %s, want %s", strings.Join(got, "; "), want)
+	}
+}
@@ -0,0 +1,228 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// MarkdownOptions customize markdown reference.
+type MarkdownOptions struct {
+	// HeadingLevel is the level of document title, from 1 to 4, default 1.
+	// Tags and schemas are one level lower, operations are two levels lower.
+	HeadingLevel int
+}
+
+// Validate checks heading level leaves room for operation headings.
+func (o MarkdownOptions) Validate() error {
+	if o.HeadingLevel < 0 || o.HeadingLevel > 4 {
+		return fmt.Errorf("invalid heading level %d, expect 1 to 4", o.HeadingLevel)
+	}
+	return nil
+}
+
+// Markdown writes markdown reference of doc, schemas have anchors "schema-<lower case name>".
+func Markdown(w io.Writer, doc *openapi3.T, opts MarkdownOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	level := opts.HeadingLevel
+	if level == 0 {
+		level = 1
+	}
+	ref := newReference(doc)
+
+	var sb strings.Builder
+	heading(&sb, level, escape(ref.Title))
+	if ref.Version != "" {
+		fmt.Fprintf(&sb, "Version: `%s`\n\n", ref.Version)
+	}
+	if ref.Description != "" {
+		fmt.Fprintf(&sb, "%s\n\n", ref.Description)
+	}
+	if len(ref.Servers) > 0 {
+		sb.WriteString("Servers:\n\n")
+		for _, server := range ref.Servers {
+			fmt.Fprintf(&sb, "- `%s`\n", server)
+		}
+		sb.WriteString("\n")
+	}
+
+	for _, g := range ref.Groups {
+		heading(&sb, level+1, escape(g.Tag))
+		if g.Description != "" {
+			fmt.Fprintf(&sb, "%s\n\n", g.Description)
+		}
+		for _, o := range g.Operations {
+			writeOperation(&sb, level+2, o)
+		}
+	}
+
+	if len(ref.Schemas) > 0 {
+		heading(&sb, level+1, "Schemas")
+		for _, s := range ref.Schemas {
+			heading(&sb, level+2, fmt.Sprintf(`<a id="%s"></a>%s`, anchor("schema", s.Name), escape(s.Name)))
+			if s.Deprecated {
+				sb.WriteString("**Deprecated**\n\n")
+			}
+			if s.Description != "" {
+				fmt.Fprintf(&sb, "%s\n\n", s.Description)
+			}
+			if len(s.Fields) > 0 {
+				writeFields(&sb, s.Fields)
+			} else {
+				fmt.Fprintf(&sb, "Type: %s\n\n", markdownType(s.Type, s.Ref))
+			}
+			if len(s.Constraints) > 0 {
+				fmt.Fprintf(&sb, "Constraints: %s\n\n", markdownConstraints(s.Constraints))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeOperation(sb *strings.Builder, level int, o *operation) {
+	title := fmt.Sprintf("`%s %s`", o.Method, o.Path)
+	if o.Summary != "" {
+		title += " " + escape(o.Summary)
+	}
+	heading(sb, level, title)
+
+	var meta []string
+	if o.OperationID != "" {
+		meta = append(meta, fmt.Sprintf("Operation ID: `%s`", o.OperationID))
+	}
+	if len(o.Security) > 0 {
+		meta = append(meta, fmt.Sprintf("Auth: `%s`", strings.Join(o.Security, "` or `")))
+	}
+	if o.Deprecated {
+		meta = append(meta, "**Deprecated**")
+	}
+	if len(meta) > 0 {
+		fmt.Fprintf(sb, "%s\n\n", strings.Join(meta, " | "))
+	}
+	if o.Description != "" {
+		fmt.Fprintf(sb, "%s\n\n", o.Description)
+	}
+
+	if len(o.Parameters) > 0 {
+		sb.WriteString("**Parameters**\n\n")
+		sb.WriteString("| Name | In | Type | Required | Description |\n")
+		sb.WriteString("| --- | --- | --- | --- | --- |\n")
+		for _, p := range o.Parameters {
+			fmt.Fprintf(sb, "| `%s` | %s | %s | %s | %s |\n",
+				p.Name, p.In, markdownType(p.Type, p.Ref), yesNo(p.Required),
+				cellDescription(p.Description, p.Deprecated, p.Constraints))
+		}
+		sb.WriteString("\n")
+	}
+
+	if b := o.RequestBody; b != nil {
+		required := ""
+		if b.Required {
+			required = ", required"
+		}
+		fmt.Fprintf(sb, "**Request body** (`%s`%s)\n\n", b.ContentType, required)
+		writeBody(sb, b)
+	}
+
+	if len(o.Responses) > 0 {
+		sb.WriteString("**Responses**\n\n")
+		for _, r := range o.Responses {
+			fmt.Fprintf(sb, "- `%s` %s", r.Status, escape(r.Description))
+			if r.Body != nil {
+				fmt.Fprintf(sb, " (`%s`)", r.Body.ContentType)
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+		for _, r := range o.Responses {
+			if r.Body == nil || (len(r.Body.Fields) == 0 && r.Body.Type == "") {
+				continue
+			}
+			fmt.Fprintf(sb, "Response `%s`:\n\n", r.Status)
+			writeBody(sb, r.Body)
+		}
+	}
+}
+
+func writeBody(sb *strings.Builder, b *body) {
+	if b.Description != "" {
+		fmt.Fprintf(sb, "%s\n\n", b.Description)
+	}
+	if len(b.Fields) > 0 {
+		if b.Ref != "" {
+			fmt.Fprintf(sb, "Schema: %s\n\n", markdownType(b.Type, b.Ref))
+		}
+		writeFields(sb, b.Fields)
+	} else if b.Type != "" {
+		fmt.Fprintf(sb, "Schema: %s\n\n", markdownType(b.Type, b.Ref))
+	}
+}
+
+func writeFields(sb *strings.Builder, fs []field) {
+	sb.WriteString("| Field | Type | Required | Description |\n")
+	sb.WriteString("| --- | --- | --- | --- |\n")
+	for _, f := range fs {
+		fmt.Fprintf(sb, "| `%s` | %s | %s | %s |\n",
+			f.Name, markdownType(f.Type, f.Ref), yesNo(f.Required),
+			cellDescription(f.Description, f.Deprecated, f.Constraints))
+	}
+	sb.WriteString("\n")
+}
+
+func heading(sb *strings.Builder, level int, title string) {
+	fmt.Fprintf(sb, "%s %s\n\n", strings.Repeat("#", level), title)
+}
+
+// markdownType returns type in code span, linked to the referenced schema.
+func markdownType(typ, ref string) string {
+	if ref == "" {
+		return fmt.Sprintf("`%s`", typ)
+	}
+	return fmt.Sprintf("[`%s`](#%s)", typ, anchor("schema", ref))
+}
+
+func markdownConstraints(cs []string) string {
+	quoted := make([]string, 0, len(cs))
+	for _, c := range cs {
+		quoted = append(quoted, fmt.Sprintf("`%s`", strings.ReplaceAll(c, "|", `\|`)))
+	}
+	return strings.Join(quoted, " ")
+}
+
+// cellDescription returns description of a table cell, lines are joined by line breaks.
+func cellDescription(desc string, deprecated bool, cs []string) string {
+	var parts []string
+	if deprecated {
+		parts = append(parts, "**Deprecated**")
+	}
+	if desc != "" {
+		lines := strings.Split(strings.TrimSpace(desc), "\n")
+		for i, line := range lines {
+			lines[i] = strings.ReplaceAll(escape(line), "|", `\|`)
+		}
+		parts = append(parts, strings.Join(lines, "<br>"))
+	}
+	if len(cs) > 0 {
+		parts = append(parts, markdownConstraints(cs))
+	}
+	return strings.Join(parts, "<br>")
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// escape escapes markdown special characters in plain text.
+func escape(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "<", "&lt;", ">", "&gt;")
+	return replacer.Replace(s)
+}
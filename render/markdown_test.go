@@ -0,0 +1,56 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMarkdown(t *testing.T) {
+	doc := load(t)
+	tests := []struct {
+		name  string
+		level int
+		want  []string
+	}{
+		{
+			name: "default level",
+			want: []string{
+				"# Book\\_store\n\nVersion: `v1`\n\nBooks.\n\nServers:\n\n- `https://example.com/v1`\n\n## book\n\nBook operations.\n\n",
+				"### `GET /books/{id}` Get \\*book\\*\n\nOperation ID: `getBook` | Auth: `jwt`\n\n",
+				"| `id` | path | `integer(int64)` | yes | book id \\| isbn<br>`>= 1` |\n",
+				"| `X-Tenant` | header | `string` | no | **Deprecated** |\n",
+				"- `200` ok (`application/json`)\n- `404` not found\n\nResponse `200`:\n\nSchema: [`Book`](#schema-book)\n\n",
+				"**Request body** (`application/json`, required)\n\n",
+				"| `meta.pages` | `integer` | no |  |\n| `title` | `string` | yes | `length >= 1` `length <= 64` |\n",
+				"### <a id=\"schema-author\"></a>Author\n\n**Deprecated**\n\n",
+				"| `author` | [`Author`](#schema-author) | no | `nullable` |\n",
+				"### <a id=\"schema-ids\"></a>Ids\n\nType: `array[integer]`\n\nConstraints: `items = 1`\n",
+			},
+		},
+		{
+			name:  "lower level",
+			level: 3,
+			want:  []string{"### Book\\_store\n", "#### book\n", "##### `POST /books`\n", "#### Schemas\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Markdown(&buf, doc, MarkdownOptions{HeadingLevel: tt.level}); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output doesn't contain %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+
+	for _, level := range []int{-1, 5} {
+		if err := Markdown(&bytes.Buffer{}, doc, MarkdownOptions{HeadingLevel: level}); err == nil {
+			t.Errorf("Markdown() with heading level %d succeeded", level)
+		}
+	}
+}
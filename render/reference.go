@@ -0,0 +1,292 @@
+package render
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jayvynl/goctl-openapi/internal/docutil"
+)
+
+// methodOrder sorts operations of the same path.
+var methodOrder = map[string]int{
+	http.MethodGet:     0,
+	http.MethodPost:    1,
+	http.MethodPut:     2,
+	http.MethodPatch:   3,
+	http.MethodDelete:  4,
+	http.MethodHead:    5,
+	http.MethodOptions: 6,
+	http.MethodTrace:   7,
+}
+
+type (
+	// reference is the document prepared for rendering, operations are grouped by the first tag.
+	reference struct {
+		Title       string
+		Version     string
+		Description string
+		Servers     []string
+		Groups      []*group
+		Schemas     []*schema
+	}
+
+	group struct {
+		Tag         string
+		Description string
+		Operations  []*operation
+	}
+
+	operation struct {
+		Method      string
+		Path        string
+		OperationID string
+		Summary     string
+		Description string
+		Deprecated  bool
+		// Security are names of security schemes, any of them is required.
+		Security    []string
+		Parameters  []parameter
+		RequestBody *body
+		Responses   []response
+	}
+
+	parameter struct {
+		Name        string
+		In          string
+		Type        string
+		Ref         string
+		Description string
+		Required    bool
+		Deprecated  bool
+		Constraints []string
+	}
+
+	body struct {
+		Description string
+		ContentType string
+		Required    bool
+		Type        string
+		Ref         string
+		Fields      []field
+	}
+
+	response struct {
+		Status      string
+		Description string
+		Body        *body
+	}
+
+	schema struct {
+		Name        string
+		Description string
+		Type        string
+		Ref         string
+		Deprecated  bool
+		Constraints []string
+		Fields      []field
+	}
+)
+
+func newReference(doc *openapi3.T) *reference {
+	ref := &reference{}
+	if doc.Info != nil {
+		ref.Title = doc.Info.Title
+		ref.Version = doc.Info.Version
+		ref.Description = doc.Info.Description
+	}
+	for _, server := range doc.Servers {
+		ref.Servers = append(ref.Servers, server.URL)
+	}
+
+	tagDescriptions := make(map[string]string, len(doc.Tags))
+	for _, tag := range doc.Tags {
+		tagDescriptions[tag.Name] = tag.Description
+	}
+	groups := make(map[string]*group)
+	if doc.Paths != nil {
+		for path, item := range doc.Paths.Map() {
+			for method, op := range item.Operations() {
+				tag := docutil.GroupTag(op.Tags)
+				g, ok := groups[tag]
+				if !ok {
+					g = &group{Tag: tag, Description: tagDescriptions[tag]}
+					groups[tag] = g
+					ref.Groups = append(ref.Groups, g)
+				}
+				g.Operations = append(g.Operations, newOperation(doc, path, method, item, op))
+			}
+		}
+	}
+	sort.Slice(ref.Groups, func(i, j int) bool {
+		return ref.Groups[i].Tag < ref.Groups[j].Tag
+	})
+	for _, g := range ref.Groups {
+		sort.Slice(g.Operations, func(i, j int) bool {
+			a, b := g.Operations[i], g.Operations[j]
+			if a.Path != b.Path {
+				return a.Path < b.Path
+			}
+			return methodOrder[a.Method] < methodOrder[b.Method]
+		})
+	}
+
+	if doc.Components != nil {
+		names := make([]string, 0, len(doc.Components.Schemas))
+		for name := range doc.Components.Schemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			ref.Schemas = append(ref.Schemas, newSchema(doc, name, doc.Components.Schemas[name]))
+		}
+	}
+	return ref
+}
+
+func newOperation(doc *openapi3.T, path, method string, item *openapi3.PathItem, op *openapi3.Operation) *operation {
+	o := &operation{
+		Method:      method,
+		Path:        path,
+		OperationID: op.OperationID,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Deprecated:  op.Deprecated,
+	}
+
+	security := doc.Security
+	if op.Security != nil {
+		security = *op.Security
+	}
+	for _, requirement := range security {
+		names := make([]string, 0, len(requirement))
+		for name := range requirement {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if len(names) > 0 {
+			o.Security = append(o.Security, strings.Join(names, " + "))
+		}
+	}
+
+	// operation parameters override path item parameters with the same name and location.
+	params := append(openapi3.Parameters{}, op.Parameters...)
+	for _, ref := range item.Parameters {
+		if p := docutil.ResolveParameter(doc, ref); p != nil && op.Parameters.GetByInAndName(p.In, p.Name) == nil {
+			params = append(params, ref)
+		}
+	}
+	for _, ref := range params {
+		p := docutil.ResolveParameter(doc, ref)
+		if p == nil {
+			continue
+		}
+		param := parameter{
+			Name:        p.Name,
+			In:          p.In,
+			Description: p.Description,
+			Required:    p.Required,
+			Deprecated:  p.Deprecated,
+		}
+		param.Type, param.Ref = typeName(doc, p.Schema)
+		if s := docutil.ResolveSchema(doc, p.Schema); s != nil && p.Schema.Ref == "" {
+			param.Constraints = constraints(s)
+		}
+		o.Parameters = append(o.Parameters, param)
+	}
+
+	if rb := docutil.ResolveRequestBody(doc, op.RequestBody); rb != nil {
+		o.RequestBody = newBody(doc, rb.Description, rb.Content)
+		if o.RequestBody != nil {
+			o.RequestBody.Required = rb.Required
+		}
+	}
+
+	if op.Responses != nil {
+		statuses := make([]string, 0, op.Responses.Len())
+		for status := range op.Responses.Map() {
+			statuses = append(statuses, status)
+		}
+		// "default" sorts after status codes.
+		sort.Strings(statuses)
+		for _, status := range statuses {
+			r := docutil.ResolveResponse(doc, op.Responses.Value(status))
+			if r == nil {
+				continue
+			}
+			resp := response{Status: status, Body: newBody(doc, "", r.Content)}
+			if r.Description != nil {
+				resp.Description = *r.Description
+			}
+			o.Responses = append(o.Responses, resp)
+		}
+	}
+	return o
+}
+
+// newBody describes the preferred media type of content, json is preferred.
+func newBody(doc *openapi3.T, description string, content openapi3.Content) *body {
+	if len(content) == 0 {
+		return nil
+	}
+	contentType := "application/json"
+	mt := content.Get(contentType)
+	if mt == nil {
+		types := make([]string, 0, len(content))
+		for t := range content {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+		contentType = types[0]
+		mt = content[contentType]
+	}
+
+	b := &body{Description: description, ContentType: contentType}
+	if mt == nil || mt.Schema == nil {
+		return b
+	}
+	b.Type, b.Ref = typeName(doc, mt.Schema)
+	if s := docutil.ResolveSchema(doc, mt.Schema); s != nil && s.Type != openapi3.TypeArray {
+		b.Fields = fields(doc, s)
+	}
+	return b
+}
+
+func newSchema(doc *openapi3.T, name string, ref *openapi3.SchemaRef) *schema {
+	s := &schema{Name: name}
+	value := docutil.ResolveSchema(doc, ref)
+	if value == nil {
+		return s
+	}
+	s.Description = value.Description
+	s.Deprecated = value.Deprecated
+	s.Constraints = constraints(value)
+	if len(value.Properties) > 0 {
+		s.Type = openapi3.TypeObject
+		s.Fields = fields(doc, value)
+	} else {
+		s.Type, s.Ref = typeName(doc, &openapi3.SchemaRef{Value: value})
+	}
+	return s
+}
+
+// anchor returns html id of name.
+func anchor(kind, name string) string {
+	var sb strings.Builder
+	sb.WriteString(kind)
+	sb.WriteByte('-')
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteByte('-')
+		}
+	}
+	return sb.String()
+}
+
+// operationAnchor returns html id of operation.
+func operationAnchor(o *operation) string {
+	return anchor("operation", o.Method+" "+o.Path)
+}
@@ -0,0 +1,223 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jayvynl/goctl-openapi/internal/docutil"
+)
+
+// field is a property of object schema, properties of inline objects are flattened with dotted names.
+type field struct {
+	Name string
+	// Depth is the nesting level of inline objects, 0 for top level properties.
+	Depth       int
+	Type        string
+	Ref         string
+	Description string
+	Required    bool
+	Deprecated  bool
+	Constraints []string
+}
+
+// typeName returns readable type of schema, and the name of referenced component schema if any.
+func typeName(doc *openapi3.T, ref *openapi3.SchemaRef) (string, string) {
+	if ref == nil {
+		return "any", ""
+	}
+	if ref.Ref != "" {
+		name := docutil.RefName(ref.Ref)
+		return name, name
+	}
+	s := ref.Value
+	if s == nil {
+		return "any", ""
+	}
+	if target := nullableTarget(s); target != nil {
+		return typeName(doc, target)
+	}
+
+	switch {
+	case len(s.OneOf) > 0:
+		return compositeName(doc, "oneOf", s.OneOf), ""
+	case len(s.AnyOf) > 0:
+		return compositeName(doc, "anyOf", s.AnyOf), ""
+	case len(s.AllOf) > 0:
+		return compositeName(doc, "allOf", s.AllOf), ""
+	}
+
+	switch s.Type {
+	case openapi3.TypeArray:
+		item, itemRef := typeName(doc, s.Items)
+		return fmt.Sprintf("array[%s]", item), itemRef
+	case openapi3.TypeObject, "":
+		if s.AdditionalProperties.Schema != nil && len(s.Properties) == 0 {
+			value, valueRef := typeName(doc, s.AdditionalProperties.Schema)
+			return fmt.Sprintf("map[string, %s]", value), valueRef
+		}
+		if s.Type == "" && len(s.Properties) == 0 {
+			return "any", ""
+		}
+		return openapi3.TypeObject, ""
+	}
+	if s.Format != "" {
+		return fmt.Sprintf("%s(%s)", s.Type, s.Format), ""
+	}
+	return s.Type, ""
+}
+
+func compositeName(doc *openapi3.T, kind string, refs openapi3.SchemaRefs) string {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		name, _ := typeName(doc, ref)
+		names = append(names, name)
+	}
+	return fmt.Sprintf("%s[%s]", kind, strings.Join(names, ", "))
+}
+
+// nullableTarget returns the referenced schema if s is a nullable wrapper {"nullable": true, "allOf": [{"$ref": ...}]}.
+func nullableTarget(s *openapi3.Schema) *openapi3.SchemaRef {
+	if len(s.AllOf) == 1 && s.Type == "" && len(s.Properties) == 0 && s.AllOf[0].Ref != "" {
+		return s.AllOf[0]
+	}
+	return nil
+}
+
+// constraints returns readable constraints of schema.
+func constraints(s *openapi3.Schema) []string {
+	var cs []string
+	if s.Nullable {
+		cs = append(cs, "nullable")
+	}
+	if s.Default != nil {
+		cs = append(cs, "default: "+formatValue(s.Default))
+	}
+	if len(s.Enum) > 0 {
+		values := make([]string, 0, len(s.Enum))
+		for _, v := range s.Enum {
+			values = append(values, formatValue(v))
+		}
+		cs = append(cs, "enum: "+strings.Join(values, ", "))
+	}
+	if s.Min != nil {
+		if s.ExclusiveMin {
+			cs = append(cs, "> "+formatNumber(*s.Min))
+		} else {
+			cs = append(cs, ">= "+formatNumber(*s.Min))
+		}
+	}
+	if s.Max != nil {
+		if s.ExclusiveMax {
+			cs = append(cs, "< "+formatNumber(*s.Max))
+		} else {
+			cs = append(cs, "<= "+formatNumber(*s.Max))
+		}
+	}
+	if s.MultipleOf != nil {
+		cs = append(cs, "multiple of "+formatNumber(*s.MultipleOf))
+	}
+	cs = append(cs, bounds("length", s.MinLength, s.MaxLength)...)
+	if s.Pattern != "" {
+		cs = append(cs, "pattern: "+s.Pattern)
+	}
+	cs = append(cs, bounds("items", s.MinItems, s.MaxItems)...)
+	if s.UniqueItems {
+		cs = append(cs, "unique items")
+	}
+	cs = append(cs, bounds("properties", s.MinProps, s.MaxProps)...)
+	if s.ReadOnly {
+		cs = append(cs, "read only")
+	}
+	if s.WriteOnly {
+		cs = append(cs, "write only")
+	}
+	return cs
+}
+
+func bounds(name string, min uint64, max *uint64) []string {
+	switch {
+	case max != nil && min == *max:
+		return []string{fmt.Sprintf("%s = %d", name, min)}
+	case max != nil && min > 0:
+		return []string{fmt.Sprintf("%s >= %d", name, min), fmt.Sprintf("%s <= %d", name, *max)}
+	case max != nil:
+		return []string{fmt.Sprintf("%s <= %d", name, *max)}
+	case min > 0:
+		return []string{fmt.Sprintf("%s >= %d", name, min)}
+	}
+	return nil
+}
+
+// fields returns properties of object schema, properties of inline objects are flattened after their parent.
+func fields(doc *openapi3.T, s *openapi3.Schema) []field {
+	return appendFields(nil, doc, s, "", 0)
+}
+
+func appendFields(fs []field, doc *openapi3.T, s *openapi3.Schema, prefix string, depth int) []field {
+	if s == nil {
+		return fs
+	}
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ref := s.Properties[name]
+		typ, typRef := typeName(doc, ref)
+		f := field{
+			Name:     prefix + name,
+			Depth:    depth,
+			Type:     typ,
+			Ref:      typRef,
+			Required: required[name],
+		}
+		if ps := ref.Value; ps != nil {
+			f.Description = ps.Description
+			f.Deprecated = ps.Deprecated
+			f.Constraints = constraints(ps)
+		}
+		fs = append(fs, f)
+
+		// expand inline objects, referenced schemas are documented separately.
+		if inline := inlineObject(ref); inline != nil {
+			fs = appendFields(fs, doc, inline, prefix+name+".", depth+1)
+		} else if ref.Ref == "" && ref.Value != nil {
+			if inline = inlineObject(ref.Value.Items); inline != nil {
+				fs = appendFields(fs, doc, inline, prefix+name+"[].", depth+1)
+			} else if inline = inlineObject(ref.Value.AdditionalProperties.Schema); inline != nil {
+				fs = appendFields(fs, doc, inline, prefix+name+".*.", depth+1)
+			}
+		}
+	}
+	return fs
+}
+
+// inlineObject returns the schema if it's an inline object with properties.
+func inlineObject(ref *openapi3.SchemaRef) *openapi3.Schema {
+	if ref == nil || ref.Ref != "" || ref.Value == nil || len(ref.Value.Properties) == 0 {
+		return nil
+	}
+	return ref.Value
+}
+
+func formatValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,219 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/invopop/yaml"
+	"github.com/jayvynl/goctl-openapi/constant"
+	"github.com/jayvynl/goctl-openapi/oas3"
+	"github.com/pkg/errors"
+)
+
+type (
+	// Rules customize generated operations declaratively, rules are applied in order.
+	Rules struct {
+		Rules []Rule `json:"rules"`
+	}
+
+	Rule struct {
+		Description string `json:"description,omitempty"`
+		// Match selects operations, empty match selects all operations.
+		Match Match `json:"match"`
+		// Extensions are added to operations, keys must start with "x-".
+		Extensions map[string]interface{} `json:"extensions,omitempty"`
+		Tags       *TagActions            `json:"tags,omitempty"`
+		// Parameters are added to operations, existing parameters with the same name and location are replaced.
+		Parameters []*openapi3.Parameter `json:"parameters,omitempty"`
+		Deprecated *bool                 `json:"deprecated,omitempty"`
+	}
+
+	// Match selects operations matching all kinds of non-empty patterns,
+	// and any pattern of a kind. Patterns are glob patterns, see path.Match.
+	Match struct {
+		Groups []string `json:"groups,omitempty"`
+		Tags   []string `json:"tags,omitempty"`
+		// Paths are route paths in openapi format, a trailing "/**" matches any sub path.
+		Paths      []string `json:"paths,omitempty"`
+		Handlers   []string `json:"handlers,omitempty"`
+		Visibility []string `json:"visibility,omitempty"`
+		// Methods are http methods, case insensitive.
+		Methods []string `json:"methods,omitempty"`
+	}
+
+	// TagActions change operation tags, tags are renamed, then removed, then added.
+	TagActions struct {
+		Rename map[string]string `json:"rename,omitempty"`
+		Remove []string          `json:"remove,omitempty"`
+		Add    []string          `json:"add,omitempty"`
+	}
+)
+
+// Load reads rules file in json or yaml format.
+func Load(filename string) (*Rules, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse parses rules in json or yaml format.
+func Parse(data []byte) (*Rules, error) {
+	var rs Rules
+	if err := yaml.Unmarshal(data, &rs); err != nil {
+		return nil, errors.WithMessage(err, "parse rules")
+	}
+	if err := rs.Validate(); err != nil {
+		return nil, err
+	}
+	return &rs, nil
+}
+
+// Validate checks rules have actions and injected parameters are valid.
+func (rs *Rules) Validate() error {
+	for i, rule := range rs.Rules {
+		if len(rule.Extensions) == 0 && rule.Tags == nil && len(rule.Parameters) == 0 && rule.Deprecated == nil {
+			return fmt.Errorf("rule %d: one of extensions, tags, parameters or deprecated is required", i)
+		}
+		for key := range rule.Extensions {
+			if !strings.HasPrefix(key, "x-") {
+				return fmt.Errorf("rule %d: extension \"%s\" must start with \"x-\"", i, key)
+			}
+		}
+		for _, param := range rule.Parameters {
+			if param == nil {
+				return fmt.Errorf("rule %d: parameter is empty", i)
+			}
+			if err := param.Validate(context.Background()); err != nil {
+				return errors.WithMessagef(err, "rule %d: parameter \"%s\"", i, param.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Transform applies matched rules to the operation, Rules is an oas3.Transformer.
+func (rs *Rules) Transform(_ *openapi3.T, oc *oas3.OperationContext) error {
+	for _, rule := range rs.Rules {
+		if rule.Match.match(oc) {
+			rule.apply(oc.Operation)
+		}
+	}
+	return nil
+}
+
+func (r Rule) apply(op *openapi3.Operation) {
+	if len(r.Extensions) > 0 && op.Extensions == nil {
+		op.Extensions = make(map[string]interface{}, len(r.Extensions))
+	}
+	for key, value := range r.Extensions {
+		op.Extensions[key] = value
+	}
+
+	if r.Tags != nil {
+		op.Tags = r.Tags.apply(op.Tags)
+	}
+
+	for _, param := range r.Parameters {
+		// copy parameter, operations should not share the same object.
+		p := *param
+		ref := &openapi3.ParameterRef{Value: &p}
+		if existing := op.Parameters.GetByInAndName(p.In, p.Name); existing != nil {
+			for i, pr := range op.Parameters {
+				if pr.Value == existing {
+					op.Parameters[i] = ref
+				}
+			}
+		} else {
+			op.Parameters = append(op.Parameters, ref)
+		}
+	}
+
+	if r.Deprecated != nil {
+		op.Deprecated = *r.Deprecated
+	}
+}
+
+func (ta *TagActions) apply(tags []string) []string {
+	result := make([]string, 0, len(tags)+len(ta.Add))
+	for _, tag := range tags {
+		if renamed, ok := ta.Rename[tag]; ok {
+			tag = renamed
+		}
+		if !contains(ta.Remove, tag) && !contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+	for _, tag := range ta.Add {
+		if !contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+func (m Match) match(oc *oas3.OperationContext) bool {
+	if len(m.Paths) > 0 {
+		matched := false
+		for _, pattern := range m.Paths {
+			if oas3.MatchPath(pattern, oc.Path) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(m.Tags) > 0 {
+		matched := false
+		for _, tag := range oc.Operation.Tags {
+			if matchAny(m.Tags, tag) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(m.Methods) > 0 && !contains(upper(m.Methods), oc.Method) {
+		return false
+	}
+
+	visibility, _ := oc.Operation.Extensions[constant.ExtensionVisibility].(string)
+	return (len(m.Groups) == 0 || matchAny(m.Groups, oc.GroupName())) &&
+		(len(m.Handlers) == 0 || matchAny(m.Handlers, oc.Route.Handler)) &&
+		(len(m.Visibility) == 0 || matchAny(m.Visibility, visibility))
+}
+
+func matchAny(patterns []string, s string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+func upper(ss []string) []string {
+	result := make([]string, len(ss))
+	for i, s := range ss {
+		result[i] = strings.ToUpper(s)
+	}
+	return result
+}
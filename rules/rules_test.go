@@ -0,0 +1,203 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jayvynl/goctl-openapi/constant"
+	"github.com/jayvynl/goctl-openapi/oas3"
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+)
+
+// operation returns context of an operation of group, handler, method and path, with tags and visibility.
+func operation(group, handler, method, path string, tags []string, visibility string) *oas3.OperationContext {
+	op := openapi3.NewOperation()
+	op.Tags = tags
+	op.Extensions = make(map[string]interface{})
+	if visibility != "" {
+		op.Extensions[constant.ExtensionVisibility] = visibility
+	}
+	return &oas3.OperationContext{
+		Group: spec.Group{Annotation: spec.Annotation{Properties: map[string]string{"group": group}}},
+		Route: spec.Route{Handler: handler},
+		Path:  path,
+		// methods of operation contexts are upper case
+		Method:    strings.ToUpper(method),
+		Operation: op,
+	}
+}
+
+func TestMatch(t *testing.T) {
+	oc := operation("book", "ListBooks", "get", "/book/list/{id}", []string{"book", "public"}, "internal")
+	tests := []struct {
+		name  string
+		match Match
+		want  bool
+	}{
+		{"empty", Match{}, true},
+		{"group", Match{Groups: []string{"author", "bo*"}}, true},
+		{"group mismatch", Match{Groups: []string{"author"}}, false},
+		{"tag", Match{Tags: []string{"pub*"}}, true},
+		{"tag mismatch", Match{Tags: []string{"private"}}, false},
+		{"path", Match{Paths: []string{"/book/list/{id}"}}, true},
+		{"path sub paths", Match{Paths: []string{"/book/**"}}, true},
+		{"path glob", Match{Paths: []string{"/book/*"}}, false},
+		{"handler", Match{Handlers: []string{"List*"}}, true},
+		{"handler mismatch", Match{Handlers: []string{"Get*"}}, false},
+		{"visibility", Match{Visibility: []string{"internal"}}, true},
+		{"visibility mismatch", Match{Visibility: []string{"public"}}, false},
+		{"method case insensitive", Match{Methods: []string{"Get"}}, true},
+		{"method mismatch", Match{Methods: []string{"post"}}, false},
+		{"all kinds", Match{Groups: []string{"book"}, Tags: []string{"book"}, Methods: []string{"get"}}, true},
+		{"one kind mismatch", Match{Groups: []string{"book"}, Methods: []string{"post"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.match.match(oc); got != tt.want {
+				t.Errorf("match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagActions(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions TagActions
+		tags    []string
+		want    []string
+	}{
+		{"rename", TagActions{Rename: map[string]string{"a": "b"}}, []string{"a", "c"}, []string{"b", "c"}},
+		{"rename merges duplicates", TagActions{Rename: map[string]string{"a": "c"}}, []string{"a", "c"}, []string{"c"}},
+		{"remove renamed", TagActions{Rename: map[string]string{"a": "b"}, Remove: []string{"b"}}, []string{"a", "c"}, []string{"c"}},
+		{"add", TagActions{Add: []string{"c", "d"}}, []string{"c"}, []string{"c", "d"}},
+		{"remove then add", TagActions{Remove: []string{"a"}, Add: []string{"a"}}, []string{"a", "b"}, []string{"b", "a"}},
+		{"no tags", TagActions{Add: []string{"a"}}, nil, []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.actions.apply(tt.tags); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{
+			name: "valid",
+			data: `
+rules:
+  - match: {groups: [book]}
+    extensions: {x-rate-limit: 100}
+  - tags: {add: [public]}
+  - parameters:
+      - {name: X-Request-Id, in: header, schema: {type: string}}
+  - deprecated: true
+`,
+		},
+		{
+			name: "json",
+			data: `{"rules": [{"deprecated": false}]}`,
+		},
+		{
+			name: "no action",
+			data: "rules:\n  - match: {groups: [book]}\n",
+			err:  "rule 0: one of extensions, tags, parameters or deprecated is required",
+		},
+		{
+			name: "invalid extension",
+			data: "rules:\n  - deprecated: true\n  - extensions: {rate-limit: 100}\n",
+			err:  `rule 1: extension "rate-limit" must start with "x-"`,
+		},
+		{
+			name: "invalid parameter",
+			data: "rules:\n  - parameters:\n      - {name: id, in: body, schema: {type: string}}\n",
+			err:  `rule 0: parameter "id"`,
+		},
+		{
+			name: "empty parameter",
+			data: "rules:\n  - parameters: [null]\n",
+			err:  "rule 0: parameter is empty",
+		},
+		{
+			name: "invalid document",
+			data: "rules: {}",
+			err:  "parse rules",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("Parse() error = %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Parse() error = %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestTransform(t *testing.T) {
+	rs, err := Parse([]byte(`
+rules:
+  - match: {groups: [book]}
+    extensions: {x-rate-limit: 100}
+    tags: {rename: {book: books}, add: [public]}
+  - match: {methods: [get]}
+    parameters:
+      - {name: page, in: query, description: replaced, schema: {type: integer}}
+      - {name: X-Request-Id, in: header, schema: {type: string}}
+  - match: {handlers: [Delete*]}
+    deprecated: true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := operation("book", "ListBooks", "get", "/books", []string{"book"}, "")
+	list.Operation.AddParameter(openapi3.NewQueryParameter("page").WithSchema(openapi3.NewIntegerSchema()))
+	del := operation("book", "DeleteBook", "delete", "/books/{id}", []string{"book"}, "")
+	author := operation("author", "ListAuthors", "get", "/authors", []string{"author"}, "")
+	for _, oc := range []*oas3.OperationContext{list, del, author} {
+		if err = rs.Transform(nil, oc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := list.Operation.Extensions["x-rate-limit"]; fmt.Sprint(got) != "100" {
+		t.Errorf("x-rate-limit = %v, want 100", got)
+	}
+	if _, ok := author.Operation.Extensions["x-rate-limit"]; ok {
+		t.Error("x-rate-limit added to operation of other group")
+	}
+	if got := fmt.Sprint(del.Operation.Tags); got != "[books public]" {
+		t.Errorf("tags = %s, want [books public]", got)
+	}
+	if len(list.Operation.Parameters) != 2 {
+		t.Fatalf("got %d parameters, want 2", len(list.Operation.Parameters))
+	}
+	if p := list.Operation.Parameters.GetByInAndName(openapi3.ParameterInQuery, "page"); p == nil || p.Description != "replaced" {
+		t.Errorf("page parameter is not replaced: %+v", p)
+	}
+	if list.Operation.Parameters[1].Value == author.Operation.Parameters[1].Value {
+		t.Error("operations share the same injected parameter")
+	}
+	if del.Operation.Parameters != nil {
+		t.Errorf("parameters added to delete operation: %v", del.Operation.Parameters)
+	}
+	if !del.Operation.Deprecated || list.Operation.Deprecated {
+		t.Errorf("deprecated = %v of delete and %v of list, want true and false", del.Operation.Deprecated, list.Operation.Deprecated)
+	}
+}
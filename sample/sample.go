@@ -0,0 +1,297 @@
+package sample
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jayvynl/goctl-openapi/internal/docutil"
+)
+
+// Options customize sample values.
+type Options struct {
+	// MaxDepth is how many times a component schema can be expanded in a value, default 1,
+	// e.g. Author -> books -> Author is expanded once with the default.
+	// Deeper recursive references are cut off by omitting optional properties and emptying arrays.
+	MaxDepth int
+	// Response generates values of responses, which include read only properties
+	// and exclude write only properties, request values are the opposite.
+	Response bool
+}
+
+// Value returns a sample request value of schema which satisfies its constraints as far as possible,
+// references are resolved against components of doc, recursive references are cut off.
+func Value(doc *openapi3.T, ref *openapi3.SchemaRef) interface{} {
+	v, _ := Generate(doc, ref, Options{})
+	return v
+}
+
+// Generate returns a sample value of schema like Value, customized by opts.
+// False is returned if the value doesn't satisfy schema, e.g. constraints are contradictory,
+// or recursive references can't be cut off without exceeding the depth limit.
+func Generate(doc *openapi3.T, ref *openapi3.SchemaRef, opts Options) (interface{}, bool) {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = 1
+	}
+	g := &generator{doc: doc, opts: opts, visiting: make(map[string]int)}
+	v, ok := g.value(ref)
+	return v, ok && !g.invalid
+}
+
+type generator struct {
+	doc  *openapi3.T
+	opts Options
+	// visiting counts expansions of component schemas being generated, for breaking cycles.
+	visiting map[string]int
+	// invalid is set if a scalar value doesn't satisfy its schema.
+	invalid bool
+}
+
+// value returns sample value of schema, false if the depth limit is exceeded.
+func (g *generator) value(ref *openapi3.SchemaRef) (interface{}, bool) {
+	if ref == nil {
+		return nil, true
+	}
+	if ref.Ref != "" {
+		name := docutil.RefName(ref.Ref)
+		if g.visiting[name] >= g.opts.MaxDepth {
+			return nil, false
+		}
+		g.visiting[name]++
+		defer func() { g.visiting[name]-- }()
+	}
+	s := g.resolve(ref)
+	if s == nil {
+		return nil, true
+	}
+
+	v, ok := g.schemaValue(s)
+	if !ok && s.Nullable {
+		return nil, true
+	}
+	return v, ok
+}
+
+func (g *generator) schemaValue(s *openapi3.Schema) (interface{}, bool) {
+	switch {
+	case s.Example != nil:
+		return s.Example, true
+	case s.Default != nil:
+		return g.check(s, s.Default), true
+	case len(s.Enum) > 0:
+		for _, v := range s.Enum {
+			if s.VisitJSON(v) == nil {
+				return v, true
+			}
+		}
+		return g.check(s, s.Enum[0]), true
+	case len(s.OneOf) > 0:
+		return g.value(s.OneOf[0])
+	case len(s.AnyOf) > 0:
+		return g.value(s.AnyOf[0])
+	case len(s.AllOf) > 0:
+		return g.allOf(s)
+	}
+
+	switch s.Type {
+	case openapi3.TypeObject, "":
+		return g.object(s)
+	case openapi3.TypeArray:
+		return g.array(s)
+	case openapi3.TypeString:
+		return g.check(s, stringValue(s)), true
+	case openapi3.TypeInteger:
+		return g.check(s, int64(numberValue(s, 1))), true
+	case openapi3.TypeNumber:
+		return g.check(s, numberValue(s, 0.5)), true
+	case openapi3.TypeBoolean:
+		return true, true
+	}
+	return nil, true
+}
+
+// check validates scalar value v against s, schemas of objects and arrays may have unresolved references.
+func (g *generator) check(s *openapi3.Schema, v interface{}) interface{} {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+	default:
+		if s.VisitJSON(v) != nil {
+			g.invalid = true
+		}
+	}
+	return v
+}
+
+func (g *generator) object(s *openapi3.Schema) (interface{}, bool) {
+	if len(s.Properties) == 0 && s.AdditionalProperties.Schema == nil && s.Type == "" {
+		return nil, true
+	}
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+	obj := make(map[string]interface{}, len(s.Properties))
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if p := s.Properties[name].Value; p != nil && (g.opts.Response && p.WriteOnly || !g.opts.Response && p.ReadOnly) {
+			continue
+		}
+		v, ok := g.value(s.Properties[name])
+		if !ok {
+			if required[name] {
+				return nil, false
+			}
+			continue
+		}
+		obj[name] = v
+	}
+	if s.AdditionalProperties.Schema != nil {
+		for i := uint64(len(obj)); i < maxUint(s.MinProps, 1); i++ {
+			v, ok := g.value(s.AdditionalProperties.Schema)
+			if !ok {
+				if i < s.MinProps {
+					return nil, false
+				}
+				break
+			}
+			obj[key(i)] = v
+		}
+	}
+	return obj, true
+}
+
+func (g *generator) array(s *openapi3.Schema) (interface{}, bool) {
+	n := maxUint(s.MinItems, 1)
+	if s.MaxItems != nil && *s.MaxItems < n {
+		n = *s.MaxItems
+	}
+	items := make([]interface{}, 0, n)
+	for i := uint64(0); i < n; i++ {
+		item, ok := g.value(s.Items)
+		if !ok {
+			if s.MinItems > 0 {
+				return nil, false
+			}
+			// an empty array is valid.
+			break
+		}
+		items = append(items, item)
+	}
+	return items, true
+}
+
+// allOf merges properties of object schemas.
+func (g *generator) allOf(s *openapi3.Schema) (interface{}, bool) {
+	if len(s.AllOf) == 1 && len(s.Properties) == 0 {
+		return g.value(s.AllOf[0])
+	}
+	merged := make(map[string]interface{})
+	self := &openapi3.Schema{Properties: s.Properties, Required: s.Required}
+	for _, ref := range append(openapi3.SchemaRefs{{Value: self}}, s.AllOf...) {
+		v, ok := g.value(ref)
+		if !ok {
+			return nil, false
+		}
+		if obj, ok := v.(map[string]interface{}); ok {
+			for k, v := range obj {
+				merged[k] = v
+			}
+		}
+	}
+	return merged, true
+}
+
+func (g *generator) resolve(ref *openapi3.SchemaRef) *openapi3.Schema {
+	for ref != nil {
+		if ref.Value != nil {
+			return ref.Value
+		}
+		if g.doc == nil || g.doc.Components == nil {
+			return nil
+		}
+		next, ok := g.doc.Components.Schemas[docutil.RefName(ref.Ref)]
+		if !ok || next == ref {
+			return nil
+		}
+		ref = next
+	}
+	return nil
+}
+
+// formats are sample values of well known string formats.
+var formats = map[string]string{
+	"date-time": "2024-01-01T00:00:00Z",
+	"date":      "2024-01-01",
+	"time":      "00:00:00",
+	"duration":  "P1D",
+	"email":     "user@example.com",
+	"uuid":      "3fa85f64-5717-4562-b3fc-2c963f66afa6",
+	"uri":       "https://example.com",
+	"url":       "https://example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"byte":      "c3RyaW5n",
+	"password":  "password",
+}
+
+func stringValue(s *openapi3.Schema) string {
+	if v, ok := formats[s.Format]; ok &&
+		uint64(len(v)) >= s.MinLength && (s.MaxLength == nil || uint64(len(v)) <= *s.MaxLength) {
+		return v
+	}
+	v := "string"
+	for uint64(len(v)) < s.MinLength {
+		v += "string"
+	}
+	if uint64(len(v)) > s.MinLength && s.MinLength > 0 {
+		v = v[:s.MinLength]
+	}
+	if s.MaxLength != nil && uint64(len(v)) > *s.MaxLength {
+		v = v[:*s.MaxLength]
+	}
+	return v
+}
+
+// numberValue returns the lower bound, or the upper bound, or zero, rounded to multiple of.
+func numberValue(s *openapi3.Schema, step float64) float64 {
+	var v float64
+	switch {
+	case s.Min != nil:
+		v = *s.Min
+		if s.ExclusiveMin {
+			v += step
+		}
+	case s.Max != nil && (*s.Max < 0 || *s.Max == 0 && s.ExclusiveMax):
+		v = *s.Max
+		if s.ExclusiveMax {
+			v -= step
+		}
+	}
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		m := *s.MultipleOf
+		rounded := math.Ceil(v/m) * m
+		if s.Min != nil && s.ExclusiveMin && rounded == *s.Min {
+			rounded += m
+		}
+		v = rounded
+	}
+	return v
+}
+
+// key returns the i-th sample key of map.
+func key(i uint64) string {
+	return "key" + strconv.FormatUint(i+1, 10)
+}
+
+func maxUint(a, b uint64) uint64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,116 @@
+package sample
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const spec = `
+openapi: 3.0.3
+info: {title: books, version: v1}
+paths: {}
+components:
+  schemas:
+    Author:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        books: {type: array, items: {$ref: "#/components/schemas/Book"}}
+    Book:
+      type: object
+      required: [title]
+      properties:
+        title: {type: string, example: Go}
+        author: {$ref: "#/components/schemas/Author"}
+    Node:
+      type: object
+      required: [next]
+      properties:
+        next: {$ref: "#/components/schemas/Node"}
+    Account:
+      type: object
+      properties:
+        id: {type: integer, readOnly: true}
+        password: {type: string, writeOnly: true}
+`
+
+func load(t *testing.T) *openapi3.T {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestGenerate(t *testing.T) {
+	doc := load(t)
+	component := func(name string) *openapi3.SchemaRef {
+		return openapi3.NewSchemaRef("#/components/schemas/"+name, nil)
+	}
+	inline := func(data string) *openapi3.SchemaRef {
+		s := openapi3.NewSchema()
+		if err := json.Unmarshal([]byte(data), s); err != nil {
+			t.Fatal(err)
+		}
+		return openapi3.NewSchemaRef("", s)
+	}
+
+	tests := []struct {
+		name  string
+		ref   *openapi3.SchemaRef
+		opts  Options
+		want  string
+		valid bool
+	}{
+		{"nil", nil, Options{}, `null`, true},
+		{"example", inline(`{"type": "string", "example": "go"}`), Options{}, `"go"`, true},
+		{"default", inline(`{"type": "integer", "default": 3}`), Options{}, `3`, true},
+		{"enum", inline(`{"type": "string", "enum": ["a", "b"], "minLength": 1}`), Options{}, `"a"`, true},
+		{"invalid default", inline(`{"type": "integer", "default": 3, "maximum": 1}`), Options{}, `3`, false},
+		{"format", inline(`{"type": "string", "format": "email"}`), Options{}, `"user@example.com"`, true},
+		{"min length", inline(`{"type": "string", "minLength": 8}`), Options{}, `"stringst"`, true},
+		{"max length", inline(`{"type": "string", "maxLength": 3}`), Options{}, `"str"`, true},
+		{"exclusive minimum", inline(`{"type": "number", "minimum": 1, "exclusiveMinimum": true}`), Options{}, `1.5`, true},
+		{"negative maximum", inline(`{"type": "integer", "maximum": -3}`), Options{}, `-3`, true},
+		{"multiple of", inline(`{"type": "integer", "minimum": 1, "multipleOf": 5}`), Options{}, `5`, true},
+		{"boolean", inline(`{"type": "boolean"}`), Options{}, `true`, true},
+		{"min items", inline(`{"type": "array", "minItems": 2, "items": {"type": "integer"}}`), Options{}, `[0,0]`, true},
+		{"max items", inline(`{"type": "array", "maxItems": 0, "items": {"type": "integer"}}`), Options{}, `[]`, true},
+		{"map", inline(`{"type": "object", "additionalProperties": {"type": "boolean"}, "minProperties": 2}`), Options{}, `{"key1":true,"key2":true}`, true},
+		{"one of", inline(`{"oneOf": [{"type": "string"}, {"type": "integer"}]}`), Options{}, `"string"`, true},
+		{"all of", inline(`{"allOf": [{"$ref": "#/components/schemas/Book"}], "properties": {"isbn": {"type": "string"}}}`), Options{},
+			`{"author":{"books":[],"name":"string"},"isbn":"string","title":"Go"}`, true},
+		{"nullable reference", inline(`{"nullable": true, "allOf": [{"$ref": "#/components/schemas/Node"}]}`), Options{}, `null`, true},
+		{"recursion cut off", component("Author"), Options{}, `{"books":[{"title":"Go"}],"name":"string"}`, true},
+		{"recursion depth", component("Author"), Options{MaxDepth: 2},
+			`{"books":[{"author":{"books":[{"title":"Go"}],"name":"string"},"title":"Go"}],"name":"string"}`, true},
+		{"required recursion", component("Node"), Options{}, `null`, false},
+		{"request", component("Account"), Options{}, `{"password":"string"}`, true},
+		{"response", component("Account"), Options{Response: true}, `{"id":0}`, true},
+		{"unresolved", component("Missing"), Options{}, `null`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, valid := Generate(doc, tt.ref, tt.opts)
+			data, err := json.Marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want || valid != tt.valid {
+				t.Errorf("Generate() = %s, %t, want %s, %t", data, valid, tt.want, tt.valid)
+			}
+		})
+	}
+}
+
+func TestValue(t *testing.T) {
+	doc := load(t)
+	data, _ := json.Marshal(Value(doc, doc.Components.Schemas["Book"]))
+	if string(data) != `{"author":{"books":[{"title":"Go"}],"name":"string"},"title":"Go"}` {
+		t.Errorf("Value() = %s", data)
+	}
+}
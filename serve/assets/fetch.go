@@ -0,0 +1,96 @@
+//go:build ignore
+
+// fetch downloads ui assets of versions in swagger/VERSION and redoc/VERSION from npm registry,
+// files are written next to VERSION files and embedded by package serve.
+//
+//	go generate ./serve
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// packages are npm packages of ui assets, keyed by ui directory, files are paths in package tarballs.
+var packages = map[string]struct {
+	name  string
+	files []string
+}{
+	"swagger": {name: "swagger-ui-dist", files: []string{"swagger-ui-bundle.js", "swagger-ui.css"}},
+	"redoc":   {name: "redoc", files: []string{"bundles/redoc.standalone.js"}},
+}
+
+func main() {
+	dir := "assets"
+	if len(os.Args) > 1 {
+		dir = os.Args[1]
+	}
+	for ui, pkg := range packages {
+		if err := fetch(filepath.Join(dir, ui), pkg.name, pkg.files); err != nil {
+			fmt.Fprintf(os.Stderr, "fetch %s: %s\n", pkg.name, err)
+			os.Exit(1)
+		}
+	}
+}
+
+func fetch(dir, name string, files []string) error {
+	version, err := os.ReadFile(filepath.Join(dir, "VERSION"))
+	if err != nil {
+		return err
+	}
+	v := strings.TrimSpace(string(version))
+	url := fmt.Sprintf("https://registry.npmjs.org/%s/-/%s-%s.tgz", name, name, v)
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", url, resp.Status)
+	}
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return err
+	}
+	wanted := make(map[string]bool, len(files))
+	for _, f := range files {
+		wanted["package/"+f] = true
+	}
+	var sums strings.Builder
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if !wanted[h.Name] {
+			continue
+		}
+		delete(wanted, h.Name)
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		base := path.Base(h.Name)
+		if err = os.WriteFile(filepath.Join(dir, base), data, 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(&sums, "%x  %s\n", sha256.Sum256(data), base)
+	}
+	for f := range wanted {
+		return fmt.Errorf("%s@%s: %s not found", name, v, strings.TrimPrefix(f, "package/"))
+	}
+	return os.WriteFile(filepath.Join(dir, "SHA256SUMS"), []byte(sums.String()), 0o644)
+}
@@ -0,0 +1,217 @@
+package serve
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"html/template"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/invopop/yaml"
+)
+
+const (
+	UISwagger = "swagger"
+	UIRedoc   = "redoc"
+
+	DefaultSpecPath = "/openapi.json"
+	DefaultDocsPath = "/docs"
+)
+
+//go:generate go run assets/fetch.go
+
+// bundleFiles are the main file of ui assets, versions are pinned in assets/<ui>/VERSION.
+// The repository doesn't contain the assets, they are embedded from assets/<ui> only if fetched by go generate.
+var bundleFiles = map[string]string{
+	UISwagger: "swagger-ui-bundle.js",
+	UIRedoc:   "redoc.standalone.js",
+}
+
+// DefaultAssetsURL are base urls of ui assets of pinned versions on jsDelivr,
+// they are used unless Options overrides them or the assets are fetched by go generate before building.
+var DefaultAssetsURL = map[string]string{
+	UISwagger: "https://cdn.jsdelivr.net/npm/swagger-ui-dist@" + bundleVersion(UISwagger),
+	UIRedoc:   "https://cdn.jsdelivr.net/npm/redoc@" + bundleVersion(UIRedoc) + "/bundles",
+}
+
+//go:embed assets
+var pages embed.FS
+
+var templates = template.Must(template.ParseFS(pages, "assets/*.html"))
+
+type (
+	// Route has the same fields as go-zero rest.Route, it can be converted by rest.Route(r),
+	// so that this package doesn't depend on go-zero runtime.
+	Route struct {
+		Method  string
+		Path    string
+		Handler http.HandlerFunc
+	}
+
+	Options struct {
+		// SpecPath is the path of openapi document, default "/openapi.json".
+		SpecPath string
+		// DocsPath is the path of documentation page, default "/docs".
+		DocsPath string
+		// UI is "swagger" or "redoc", default "swagger".
+		UI string
+		// Title of documentation page, default title in document info.
+		Title string
+		// Assets are static files of the ui served under DocsPath + "/assets/", e.g. files of swagger-ui-dist
+		// embedded by the service, so that the page works offline.
+		Assets fs.FS
+		// AssetsURL is the base url of static files of the ui if Assets is nil, default the pinned version on jsDelivr.
+		AssetsURL string
+	}
+)
+
+// Routes returns routes serving the openapi document spec in json or yaml format, and its documentation page.
+//
+//	//go:embed openapi.json
+//	var spec []byte
+//
+//	for _, r := range serve.Routes(spec, serve.Options{}) {
+//		server.AddRoute(rest.Route(r))
+//	}
+func Routes(spec []byte, opts Options) []Route {
+	opts = opts.withDefaults(spec)
+	routes := []Route{
+		{Method: http.MethodGet, Path: opts.SpecPath, Handler: SpecHandler(spec)},
+		{Method: http.MethodGet, Path: opts.DocsPath, Handler: DocsHandler(opts)},
+	}
+	if assets := opts.assets(); assets != nil {
+		routes = append(routes, Route{
+			Method:  http.MethodGet,
+			Path:    path.Join(opts.DocsPath, "assets", ":file"),
+			Handler: AssetsHandler(assets),
+		})
+	}
+	return routes
+}
+
+// SpecHandler serves the openapi document spec in json or yaml format.
+func SpecHandler(spec []byte) http.HandlerFunc {
+	contentType := "application/yaml"
+	if json.Valid(spec) {
+		contentType = "application/json"
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write(spec)
+	}
+}
+
+// DocsHandler serves the documentation page of opts.UI, which loads the document from opts.SpecPath.
+// Assets are loaded from DocsPath + "/assets/" unless opts.AssetsURL is set.
+func DocsHandler(opts Options) http.HandlerFunc {
+	opts = opts.withDefaults(nil)
+	assetsURL := opts.AssetsURL
+	if opts.assets() != nil {
+		assetsURL = path.Join(opts.DocsPath, "assets")
+	}
+
+	var buf bytes.Buffer
+	err := templates.ExecuteTemplate(&buf, opts.UI+".html", struct {
+		Title     string
+		SpecURL   string
+		AssetsURL string
+	}{
+		Title:     opts.Title,
+		SpecURL:   opts.SpecPath,
+		AssetsURL: assetsURL,
+	})
+	page := buf.Bytes()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(page)
+	}
+}
+
+// AssetsHandler serves files in the root directory of assets by the last element of request path.
+func AssetsHandler(assets fs.FS) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := path.Base(r.URL.Path)
+		data, err := fs.ReadFile(assets, name)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		_, _ = w.Write(data)
+	}
+}
+
+func (o Options) withDefaults(spec []byte) Options {
+	if o.SpecPath == "" {
+		o.SpecPath = DefaultSpecPath
+	}
+	if o.DocsPath == "" {
+		o.DocsPath = DefaultDocsPath
+	}
+	o.DocsPath = strings.TrimSuffix(o.DocsPath, "/")
+	if o.UI != UIRedoc {
+		o.UI = UISwagger
+	}
+	if o.AssetsURL == "" && o.Assets == nil && bundle(o.UI) == nil {
+		o.AssetsURL = DefaultAssetsURL[o.UI]
+	}
+	o.AssetsURL = strings.TrimSuffix(o.AssetsURL, "/")
+	if o.Title == "" && spec != nil {
+		o.Title = specTitle(spec)
+	}
+	if o.Title == "" {
+		o.Title = "API documentation"
+	}
+	return o
+}
+
+// assets returns static files served under DocsPath + "/assets/", nil if assets are loaded from AssetsURL.
+func (o Options) assets() fs.FS {
+	if o.Assets != nil {
+		return o.Assets
+	}
+	if o.AssetsURL != "" {
+		return nil
+	}
+	return bundle(o.UI)
+}
+
+// bundle returns the embedded assets of ui, nil if they are not fetched.
+func bundle(ui string) fs.FS {
+	sub, err := fs.Sub(pages, path.Join("assets", ui))
+	if err != nil {
+		return nil
+	}
+	if _, err = fs.Stat(sub, bundleFiles[ui]); err != nil {
+		return nil
+	}
+	return sub
+}
+
+// bundleVersion returns the pinned version of ui assets.
+func bundleVersion(ui string) string {
+	data, _ := fs.ReadFile(pages, path.Join("assets", ui, "VERSION"))
+	return strings.TrimSpace(string(data))
+}
+
+// specTitle returns title in document info.
+func specTitle(spec []byte) string {
+	var doc struct {
+		Info struct {
+			Title string `json:"title"`
+		} `json:"info"`
+	}
+	_ = yaml.Unmarshal(spec, &doc)
+	return doc.Info.Title
+}
@@ -0,0 +1,129 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var spec = []byte(`{"openapi":"3.0.3","info":{"title":"Books","version":"v1"},"paths":{}}`)
+
+func TestRoutes(t *testing.T) {
+	custom := fstest.MapFS{"swagger-ui-bundle.js": {Data: []byte("bundle")}}
+	tests := []struct {
+		name   string
+		opts   Options
+		paths  []string
+		assets string // expected assets url in the page
+	}{
+		{
+			name:   "custom assets",
+			opts:   Options{Assets: custom},
+			paths:  []string{"/openapi.json", "/docs", "/docs/assets/:file"},
+			assets: `/docs/assets/swagger-ui-bundle.js`,
+		},
+		{
+			name:   "assets url",
+			opts:   Options{AssetsURL: "https://cdn.example.com/swagger/", DocsPath: "/api/docs/"},
+			paths:  []string{"/openapi.json", "/api/docs"},
+			assets: `https://cdn.example.com/swagger/swagger-ui-bundle.js`,
+		},
+		{
+			name:   "redoc with custom spec path",
+			opts:   Options{UI: UIRedoc, SpecPath: "/spec.yaml", AssetsURL: "https://cdn.example.com/redoc"},
+			paths:  []string{"/spec.yaml", "/docs"},
+			assets: `https://cdn.example.com/redoc/redoc.standalone.js`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			routes := Routes(spec, tt.opts)
+			var paths []string
+			for _, r := range routes {
+				paths = append(paths, r.Path)
+			}
+			if strings.Join(paths, " ") != strings.Join(tt.paths, " ") {
+				t.Fatalf("paths = %v, want %v", paths, tt.paths)
+			}
+
+			w := httptest.NewRecorder()
+			routes[1].Handler(w, httptest.NewRequest(http.MethodGet, routes[1].Path, nil))
+			page := w.Body.String()
+			if w.Code != http.StatusOK || !strings.Contains(page, tt.assets) {
+				t.Errorf("page (%d) doesn't load %s:\n%s", w.Code, tt.assets, page)
+			}
+			if !strings.Contains(page, "<title>Books</title>") {
+				t.Errorf("page doesn't have title of the document:\n%s", page)
+			}
+		})
+	}
+}
+
+func TestDefaultAssets(t *testing.T) {
+	for _, ui := range []string{UISwagger, UIRedoc} {
+		if bundleVersion(ui) == "" {
+			t.Errorf("%s: version is not pinned", ui)
+		}
+		w := httptest.NewRecorder()
+		DocsHandler(Options{UI: ui})(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+		want := "/docs/assets/" + bundleFiles[ui]
+		if bundle(ui) == nil {
+			// assets are not fetched by go generate, pages fall back to the pinned version.
+			want = DefaultAssetsURL[ui] + "/" + bundleFiles[ui]
+		}
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("%s: page doesn't load %s", ui, want)
+		}
+		if strings.Contains(w.Body.String(), "latest") {
+			t.Errorf("%s: page loads unpinned assets", ui)
+		}
+	}
+}
+
+func TestSpecHandler(t *testing.T) {
+	tests := []struct {
+		spec        string
+		contentType string
+	}{
+		{`{"openapi":"3.0.3"}`, "application/json"},
+		{"openapi: 3.0.3\n", "application/yaml"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		SpecHandler([]byte(tt.spec))(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+		if got := w.Header().Get("Content-Type"); got != tt.contentType {
+			t.Errorf("content type of %q = %s, want %s", tt.spec, got, tt.contentType)
+		}
+		if w.Body.String() != tt.spec {
+			t.Errorf("body = %q, want %q", w.Body.String(), tt.spec)
+		}
+	}
+}
+
+func TestAssetsHandler(t *testing.T) {
+	assets := fstest.MapFS{
+		"swagger-ui.css":       {Data: []byte("body{}")},
+		"swagger-ui-bundle.js": {Data: []byte("bundle")},
+	}
+	tests := []struct {
+		path        string
+		code        int
+		contentType string
+	}{
+		{"/docs/assets/swagger-ui.css", http.StatusOK, "text/css; charset=utf-8"},
+		{"/docs/assets/swagger-ui-bundle.js", http.StatusOK, "text/javascript; charset=utf-8"},
+		{"/docs/assets/missing.js", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		AssetsHandler(assets)(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.code {
+			t.Errorf("%s: status = %d, want %d", tt.path, w.Code, tt.code)
+		}
+		if tt.contentType != "" && w.Header().Get("Content-Type") != tt.contentType {
+			t.Errorf("%s: content type = %s, want %s", tt.path, w.Header().Get("Content-Type"), tt.contentType)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package typescript
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jayvynl/goctl-openapi/internal/docutil"
+	"github.com/jayvynl/goctl-openapi/oas3"
+)
+
+// Generate returns typescript definitions of doc, which are valid in both ".ts" and ".d.ts" files.
+// Every component schema becomes an interface or a type alias, enums become unions of literals,
+// optional fields are marked by "?", nullable values are unions with null.
+// Operations have "<OperationID>Request" type combining parameters and request body,
+// and "<OperationID>Response" type of the successful response.
+func Generate(doc *openapi3.T) []byte {
+	g := &generator{doc: doc}
+	g.sb.WriteString("// Code generated by goctl-openapi. DO NOT EDIT.\n")
+
+	var names []string
+	if doc.Components != nil {
+		for name := range doc.Components.Schemas {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	components := make(map[string]bool, len(names))
+	for _, name := range names {
+		components[name] = true
+		g.writeSchema(name, doc.Components.Schemas[name])
+	}
+
+	paths := doc.Paths.InMatchingOrder()
+	sort.Strings(paths)
+	for _, path := range paths {
+		item := doc.Paths.Value(path)
+		for _, method := range docutil.Methods(item) {
+			g.writeOperation(path, method, item, item.GetOperation(method), components)
+		}
+	}
+	return []byte(g.sb.String())
+}
+
+type generator struct {
+	doc *openapi3.T
+	sb  strings.Builder
+}
+
+func (g *generator) writeSchema(name string, ref *openapi3.SchemaRef) {
+	s := ref.Value
+	if s == nil {
+		fmt.Fprintf(&g.sb, "\nexport type %s = %s;\n", name, g.typeOf(ref, 0))
+		return
+	}
+	g.sb.WriteString("\n")
+	writeComment(&g.sb, "", s)
+	if s.Type == openapi3.TypeObject && len(s.AllOf) == 0 && s.AdditionalProperties.Schema == nil && !s.Nullable {
+		fmt.Fprintf(&g.sb, "export interface %s %s\n", name, g.object(s, 0))
+		return
+	}
+	fmt.Fprintf(&g.sb, "export type %s = %s;\n", name, g.typeOf(ref, 0))
+}
+
+func (g *generator) writeOperation(path, method string, item *openapi3.PathItem, op *openapi3.Operation, components map[string]bool) {
+	name := op.OperationID
+	if name == "" {
+		name = method + " " + path
+	}
+	name = oas3.ConvertName("pascal", name)
+
+	// parameters
+	params := append(openapi3.Parameters{}, op.Parameters...)
+	for _, ref := range item.Parameters {
+		if p := docutil.ResolveParameter(g.doc, ref); p != nil && op.Parameters.GetByInAndName(p.In, p.Name) == nil {
+			params = append(params, ref)
+		}
+	}
+	var body *openapi3.SchemaRef
+	if rb := docutil.ResolveRequestBody(g.doc, op.RequestBody); rb != nil {
+		if mt := docutil.FirstMediaType(rb.Content); mt != nil {
+			body = mt.Schema
+		}
+	}
+	bodyFields := make(map[string]bool)
+	if s := docutil.ResolveSchema(g.doc, body); s != nil {
+		for field := range s.Properties {
+			bodyFields[field] = true
+		}
+	}
+
+	var fields strings.Builder
+	for _, ref := range params {
+		p := docutil.ResolveParameter(g.doc, ref)
+		if p == nil || p.In == openapi3.ParameterInCookie || bodyFields[p.Name] {
+			continue
+		}
+		if p.Description != "" || p.Deprecated {
+			writeComment(&fields, "  ", &openapi3.Schema{Description: p.Description, Deprecated: p.Deprecated})
+		}
+		optional := "?"
+		if p.Required {
+			optional = ""
+		}
+		fmt.Fprintf(&fields, "  %s%s: %s;\n", propertyName(p.Name), optional, g.typeOf(p.Schema, 1))
+	}
+	var parts []string
+	if fields.Len() > 0 {
+		parts = append(parts, "{\n"+fields.String()+"}")
+	}
+	if body != nil {
+		parts = append(parts, g.typeOf(body, 0))
+	}
+	request := "void"
+	if len(parts) > 0 {
+		request = strings.Join(parts, " & ")
+	}
+
+	response := "void"
+	if op.Responses != nil {
+		_, ref := docutil.SuccessResponse(op.Responses)
+		if r := docutil.ResolveResponse(g.doc, ref); r != nil {
+			if mt := docutil.FirstMediaType(r.Content); mt != nil && mt.Schema != nil {
+				response = g.typeOf(mt.Schema, 0)
+			}
+		}
+	}
+
+	fmt.Fprintf(&g.sb, "\n/** %s %s", method, path)
+	if op.Summary != "" {
+		fmt.Fprintf(&g.sb, " %s", comment(op.Summary))
+	}
+	g.sb.WriteString(" */\n")
+	for _, t := range []struct{ suffix, typ string }{{"Request", request}, {"Response", response}} {
+		typeName := name + t.suffix
+		if components[typeName] {
+			if t.typ == typeName {
+				// the component is the type.
+				continue
+			}
+			typeName = name + "Operation" + t.suffix
+		}
+		fmt.Fprintf(&g.sb, "export type %s = %s;\n", typeName, t.typ)
+	}
+}
+
+// typeOf returns typescript type expression of schema, depth is the indent level of inline objects.
+func (g *generator) typeOf(ref *openapi3.SchemaRef, depth int) string {
+	if ref == nil {
+		return "unknown"
+	}
+	if ref.Ref != "" {
+		return docutil.RefName(ref.Ref)
+	}
+	s := ref.Value
+	if s == nil {
+		return "unknown"
+	}
+
+	var t string
+	switch {
+	case len(s.Enum) > 0:
+		literals := make([]string, 0, len(s.Enum))
+		for _, e := range s.Enum {
+			literals = append(literals, literal(e))
+		}
+		t = strings.Join(literals, " | ")
+	case len(s.AllOf) > 0:
+		types := make([]string, 0, len(s.AllOf))
+		for _, r := range s.AllOf {
+			t := g.typeOf(r, depth)
+			if strings.Contains(t, "|") {
+				t = "(" + t + ")"
+			}
+			types = append(types, t)
+		}
+		if len(s.Properties) > 0 {
+			types = append(types, g.object(s, depth))
+		}
+		t = strings.Join(types, " & ")
+	case len(s.OneOf) > 0 || len(s.AnyOf) > 0:
+		refs := s.OneOf
+		if len(refs) == 0 {
+			refs = s.AnyOf
+		}
+		types := make([]string, 0, len(refs))
+		for _, r := range refs {
+			types = append(types, g.typeOf(r, depth))
+		}
+		t = strings.Join(types, " | ")
+	default:
+		switch s.Type {
+		case openapi3.TypeString:
+			t = "string"
+		case openapi3.TypeInteger, openapi3.TypeNumber:
+			t = "number"
+		case openapi3.TypeBoolean:
+			t = "boolean"
+		case openapi3.TypeArray:
+			item := g.typeOf(s.Items, depth)
+			if strings.ContainsAny(item, "|&") {
+				item = "(" + item + ")"
+			}
+			t = item + "[]"
+		case openapi3.TypeObject, "":
+			switch {
+			case len(s.Properties) > 0:
+				t = g.object(s, depth)
+			case s.AdditionalProperties.Schema != nil:
+				t = "Record<string, " + g.typeOf(s.AdditionalProperties.Schema, depth) + ">"
+			case s.Type == openapi3.TypeObject:
+				t = "Record<string, unknown>"
+			default:
+				t = "unknown"
+			}
+		default:
+			t = "unknown"
+		}
+	}
+	if s.Nullable {
+		t += " | null"
+	}
+	return t
+}
+
+// object returns object literal type of properties, fields are sorted by name.
+func (g *generator) object(s *openapi3.Schema, depth int) string {
+	if len(s.Properties) == 0 {
+		return "{}"
+	}
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	indent := strings.Repeat("  ", depth+1)
+	var sb strings.Builder
+	sb.WriteString("{\n")
+	for _, name := range names {
+		p := s.Properties[name]
+		if p.Value != nil {
+			writeComment(&sb, indent, p.Value)
+		}
+		optional := "?"
+		if required[name] {
+			optional = ""
+		}
+		fmt.Fprintf(&sb, "%s%s%s: %s;\n", indent, propertyName(name), optional, g.typeOf(p, depth+1))
+	}
+	sb.WriteString(strings.Repeat("  ", depth) + "}")
+	return sb.String()
+}
+
+// writeComment writes jsdoc of description, default value and deprecation.
+func writeComment(sb *strings.Builder, indent string, s *openapi3.Schema) {
+	var lines []string
+	if s.Description != "" {
+		for _, line := range strings.Split(strings.TrimSpace(s.Description), "\n") {
+			lines = append(lines, comment(line))
+		}
+	}
+	if s.Default != nil {
+		lines = append(lines, "@default "+literal(s.Default))
+	}
+	if s.Deprecated {
+		lines = append(lines, "@deprecated")
+	}
+	switch len(lines) {
+	case 0:
+	case 1:
+		fmt.Fprintf(sb, "%s/** %s */\n", indent, lines[0])
+	default:
+		fmt.Fprintf(sb, "%s/**\n", indent)
+		for _, line := range lines {
+			fmt.Fprintf(sb, "%s * %s\n", indent, line)
+		}
+		fmt.Fprintf(sb, "%s */\n", indent)
+	}
+}
+
+// comment escapes end of comment.
+func comment(s string) string {
+	return strings.ReplaceAll(strings.TrimSpace(s), "*/", "*\\/")
+}
+
+// literal returns typescript literal of enum or default value.
+func literal(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// propertyName quotes name if it's not a valid identifier.
+func propertyName(name string) string {
+	for i, r := range name {
+		if !(r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return strconv.Quote(name)
+		}
+	}
+	if name == "" {
+		return `""`
+	}
+	return name
+}
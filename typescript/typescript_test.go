@@ -0,0 +1,118 @@
+package typescript
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const spec = `
+openapi: 3.0.3
+info: {title: books, version: v1}
+paths:
+  /books/{id}:
+    get:
+      operationId: getBook
+      summary: Get book */
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+        - {name: X-Tenant, in: header, description: tenant, schema: {type: string}}
+        - {name: sid, in: cookie, schema: {type: string}}
+      responses:
+        "201": {description: created}
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Book"}
+    put:
+      operationId: updateBook
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/Book"}
+      responses: {default: {description: ok}}
+  /ping:
+    get:
+      responses:
+        default:
+          description: pong
+          content:
+            text/plain:
+              schema: {type: string}
+components:
+  schemas:
+    Status: {type: string, enum: [draft, published]}
+    Tags: {type: object, additionalProperties: {type: array, items: {type: string, nullable: true}}}
+    Book:
+      type: object
+      description: A book.
+      required: [id, name]
+      properties:
+        id: {type: integer}
+        name: {type: string, default: go, deprecated: true}
+        status: {$ref: "#/components/schemas/Status"}
+        author: {nullable: true, allOf: [{$ref: "#/components/schemas/Author"}]}
+        content-type: {type: string}
+        extra: {type: object, properties: {pages: {type: integer}}}
+    Author:
+      type: object
+      properties: {name: {type: string}}
+    GetBookRequest: {type: object}
+`
+
+func TestGenerate(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := string(Generate(doc))
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"header", "// Code generated by goctl-openapi. DO NOT EDIT.\n"},
+		{"enum", "export type Status = \"draft\" | \"published\";\n"},
+		{"record", "export type Tags = Record<string, (string | null)[]>;\n"},
+		{"interface", "/** A book. */\nexport interface Book {\n"},
+		{"nullable reference", "  author?: Author | null;\n"},
+		{"quoted property", "  \"content-type\"?: string;\n"},
+		{"comment", "  /**\n   * @default \"go\"\n   * @deprecated\n   */\n  name: string;\n"},
+		{"nested object", "  extra?: {\n    pages?: number;\n  };\n"},
+		{"escaped summary", "/** GET /books/{id} Get book *\\/ */\n"},
+		{"conflicting request", "export type GetBookOperationRequest = {\n  id: number;\n  /** tenant */\n  \"X-Tenant\"?: string;\n};\n"},
+		{"lowest success response", "export type GetBookResponse = Book;\n"},
+		{"request with body", "export type UpdateBookRequest = Book;\n"},
+		{"default response", "export type UpdateBookResponse = void;\n"},
+		{"operation without id", "export type GetPingRequest = void;\nexport type GetPingResponse = string;\n"},
+	}
+	for _, tt := range tests {
+		if !strings.Contains(ts, tt.want) {
+			t.Errorf("%s: output doesn't contain %q:\n%s", tt.name, tt.want, ts)
+		}
+	}
+	if strings.Contains(ts, "sid") {
+		t.Errorf("cookie parameter is in request type:\n%s", ts)
+	}
+}
+
+func TestPropertyName(t *testing.T) {
+	for name, want := range map[string]string{
+		"name":   "name",
+		"$ref":   "$ref",
+		"a1":     "a1",
+		"1a":     `"1a"`,
+		"a-b":    `"a-b"`,
+		"":       `""`,
+		"名称":     `"名称"`,
+		"_value": "_value",
+	} {
+		if got := propertyName(name); got != want {
+			t.Errorf("propertyName(%q) = %s, want %s", name, got, want)
+		}
+	}
+}
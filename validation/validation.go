@@ -0,0 +1,191 @@
+package validation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/pkg/errors"
+)
+
+type (
+	Options struct {
+		// ValidateResponses validates responses of handlers, it's intended for test environments,
+		// because responses are buffered.
+		ValidateResponses bool
+		// ErrorHandler writes invalid request error, default writes error message with status 400,
+		// the same as go-zero httpx.Error without error handler.
+		// Use httpx.ErrorCtx to write errors the same way as handlers.
+		ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+		// ResponseErrorHandler writes invalid response error instead of the response,
+		// default writes error message with status 500.
+		ResponseErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+	}
+
+	// Error is an invalid request or response, its message doesn't contain schema details.
+	Error struct {
+		Message string
+		Err     error
+	}
+
+	// responseRecorder buffers response for validation.
+	responseRecorder struct {
+		header http.Header
+		status int
+		body   bytes.Buffer
+	}
+)
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Middleware returns a go-zero middleware validating requests against openapi document spec in json or yaml format,
+// it can be passed to rest.Server.Use. Requests of routes not in the document are not validated.
+// Security requirements are not validated, they are handled by go-zero jwt.
+func Middleware(spec []byte, opts Options) (func(next http.HandlerFunc) http.HandlerFunc, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		return nil, errors.WithMessage(err, "load openapi document")
+	}
+	if err = doc.Validate(context.Background()); err != nil {
+		return nil, errors.WithMessage(err, "invalid openapi document")
+	}
+	// go-zero routes don't contain host, servers are ignored when routes are matched.
+	doc.Servers = nil
+	for _, item := range doc.Paths.Map() {
+		item.Servers = nil
+		for _, op := range item.Operations() {
+			op.Servers = nil
+		}
+	}
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.ErrorHandler == nil {
+		opts.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+	if opts.ResponseErrorHandler == nil {
+		opts.ResponseErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+	filterOpts := &openapi3filter.Options{
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		// defaults are filled by go-zero httpx.Parse.
+		SkipSettingDefaults: true,
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := router.FindRoute(r)
+			if err != nil {
+				next(w, r)
+				return
+			}
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    filterOpts,
+			}
+			if err = openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+				opts.ErrorHandler(w, r, &Error{Message: message(err), Err: err})
+				return
+			}
+			if !opts.ValidateResponses {
+				next(w, r)
+				return
+			}
+
+			rec := &responseRecorder{header: make(http.Header), status: http.StatusOK}
+			next(rec, r)
+			err = openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: input,
+				Status:                 rec.status,
+				Header:                 rec.header,
+				Body:                   io.NopCloser(bytes.NewReader(rec.body.Bytes())),
+				Options:                filterOpts,
+			})
+			if err != nil {
+				opts.ResponseErrorHandler(w, r, &Error{Message: message(err), Err: err})
+				return
+			}
+			rec.writeTo(w)
+		}
+	}, nil
+}
+
+// message formats validation error without schema and value details.
+func message(err error) string {
+	var (
+		reqErr    *openapi3filter.RequestError
+		respErr   *openapi3filter.ResponseError
+		schemaErr *openapi3.SchemaError
+		prefix    string
+	)
+	switch {
+	case errors.As(err, &reqErr):
+		if reqErr.Parameter != nil {
+			prefix = fmt.Sprintf("%s parameter \"%s\"", reqErr.Parameter.In, reqErr.Parameter.Name)
+		} else {
+			prefix = "request body"
+		}
+	case errors.As(err, &respErr):
+		prefix = "response body"
+	default:
+		return err.Error()
+	}
+
+	if !errors.As(err, &schemaErr) {
+		if reqErr != nil && reqErr.Reason != "" {
+			return fmt.Sprintf("%s: %s", prefix, reqErr.Reason)
+		}
+		if respErr != nil && respErr.Reason != "" {
+			return fmt.Sprintf("%s: %s", prefix, respErr.Reason)
+		}
+		if errors.Unwrap(err) != nil {
+			return fmt.Sprintf("%s: %s", prefix, errors.Unwrap(err))
+		}
+		return err.Error()
+	}
+	if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+		return fmt.Sprintf("%s: field \"%s\": %s", prefix, strings.Join(pointer, "."), schemaErr.Reason)
+	}
+	return fmt.Sprintf("%s: %s", prefix, schemaErr.Reason)
+}
+
+func (rr *responseRecorder) Header() http.Header {
+	return rr.header
+}
+
+func (rr *responseRecorder) Write(data []byte) (int, error) {
+	return rr.body.Write(data)
+}
+
+func (rr *responseRecorder) WriteHeader(status int) {
+	rr.status = status
+}
+
+func (rr *responseRecorder) writeTo(w http.ResponseWriter) {
+	for key, values := range rr.header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(rr.status)
+	_, _ = w.Write(rr.body.Bytes())
+}
@@ -0,0 +1,253 @@
+package validation
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const spec = `
+openapi: 3.0.3
+info: {title: test, version: v1}
+servers: [{url: "https://example.com/v1"}]
+paths:
+  /books/{id}:
+    get:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+        - {name: page, in: query, schema: {type: integer, minimum: 1}}
+        - {name: X-Tenant, in: header, required: true, schema: {type: string}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [name]
+                properties:
+                  name: {type: string}
+  /books:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string, minLength: 2}
+                tags: {type: array, items: {type: string, enum: [a, b]}}
+      responses:
+        "200": {description: ok}
+`
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		header  map[string]string
+		body    string
+		status  int
+		message string
+	}{
+		{
+			name:   "valid query",
+			method: http.MethodGet,
+			target: "/books/1?page=2",
+			header: map[string]string{"X-Tenant": "t"},
+			status: http.StatusOK,
+		},
+		{
+			name:    "invalid path",
+			method:  http.MethodGet,
+			target:  "/books/abc",
+			header:  map[string]string{"X-Tenant": "t"},
+			status:  http.StatusBadRequest,
+			message: `path parameter "id": `,
+		},
+		{
+			name:    "query out of range",
+			method:  http.MethodGet,
+			target:  "/books/1?page=0",
+			header:  map[string]string{"X-Tenant": "t"},
+			status:  http.StatusBadRequest,
+			message: `query parameter "page": number must be at least 1`,
+		},
+		{
+			name:    "missing header",
+			method:  http.MethodGet,
+			target:  "/books/1",
+			status:  http.StatusBadRequest,
+			message: `header parameter "X-Tenant": value is required but missing`,
+		},
+		{
+			name:   "valid body",
+			method: http.MethodPost,
+			target: "/books",
+			header: map[string]string{"Content-Type": "application/json"},
+			body:   `{"name": "go", "tags": ["a"]}`,
+			status: http.StatusOK,
+		},
+		{
+			name:    "missing body",
+			method:  http.MethodPost,
+			target:  "/books",
+			header:  map[string]string{"Content-Type": "application/json"},
+			status:  http.StatusBadRequest,
+			message: "request body: value is required but missing",
+		},
+		{
+			name:    "missing field",
+			method:  http.MethodPost,
+			target:  "/books",
+			header:  map[string]string{"Content-Type": "application/json"},
+			body:    `{}`,
+			status:  http.StatusBadRequest,
+			message: `request body: field "name": property "name" is missing`,
+		},
+		{
+			name:    "nested field",
+			method:  http.MethodPost,
+			target:  "/books",
+			header:  map[string]string{"Content-Type": "application/json"},
+			body:    `{"name": "go", "tags": ["c"]}`,
+			status:  http.StatusBadRequest,
+			message: `request body: field "tags.0": value is not one of the allowed values`,
+		},
+		{
+			name:   "unknown route",
+			method: http.MethodDelete,
+			target: "/authors",
+			status: http.StatusNoContent,
+		},
+	}
+
+	mw, err := Middleware([]byte(spec), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := mw(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			r := httptest.NewRequest(tt.method, tt.target, body)
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			handler(w, r)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d, body: %s", w.Code, tt.status, w.Body)
+			}
+			if !strings.Contains(w.Body.String(), tt.message) {
+				t.Errorf("body = %s, want message %s", w.Body, tt.message)
+			}
+		})
+	}
+}
+
+func TestMiddlewareResponses(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   int
+	}{
+		{"valid", http.StatusOK, `{"name": "go"}`, http.StatusOK},
+		{"missing field", http.StatusOK, `{}`, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handled error
+			mw, err := Middleware([]byte(spec), Options{
+				ValidateResponses: true,
+				ResponseErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+					handled = err
+					w.WriteHeader(http.StatusInternalServerError)
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			handler := mw(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/books/1", nil)
+			r.Header.Set("X-Tenant", "t")
+			w := httptest.NewRecorder()
+			handler(w, r)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+			if tt.want == http.StatusOK {
+				if w.Body.String() != tt.body || w.Header().Get("Content-Type") != "application/json" {
+					t.Errorf("response is not written through: %s %v", w.Body, w.Header())
+				}
+				return
+			}
+			var verr *Error
+			if !errors.As(handled, &verr) || strings.Contains(verr.Message, "Schema:") {
+				t.Errorf("error = %v, want validation error without schema details", handled)
+			}
+		})
+	}
+}
+
+func TestMiddlewareErrorHandler(t *testing.T) {
+	var handled error
+	mw, err := Middleware([]byte(spec), Options{
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			handled = err
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	mw(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler called with invalid request")
+	})(w, httptest.NewRequest(http.MethodGet, "/books/1?page=0", nil))
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+	var verr *Error
+	if !errors.As(handled, &verr) || verr.Err == nil {
+		t.Errorf("error = %v, want *Error wrapping validation error", handled)
+	}
+}
+
+func TestMiddlewareInvalidSpec(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		err  string
+	}{
+		{"not a document", "[", "load openapi document"},
+		{"invalid document", "openapi: 3.0.3\ninfo: {title: test}\npaths: {}", "invalid openapi document"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Middleware([]byte(tt.spec), Options{}); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Middleware() error = %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/zeromicro/go-zero/tools/goctl/pkg/parser/api/ast"
+	"github.com/zeromicro/go-zero/tools/goctl/pkg/parser/api/parser"
+)
+
+const (
+	// watchInterval is how often watched files are polled.
+	watchInterval = 500 * time.Millisecond
+	// watchDebounce is how long changes must settle before regenerating, editors usually write files in several steps.
+	watchDebounce = 300 * time.Millisecond
+)
+
+// fileState is the modification time and size of a file, zero if the file doesn't exist.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// watcher polls an api file and files it imports for changes.
+type watcher struct {
+	filename string
+	// interval is how often files are polled.
+	interval time.Duration
+	// debounce is how long changes must settle.
+	debounce time.Duration
+	// sleep waits between polls.
+	sleep func(time.Duration)
+	files []string
+	last  map[string]fileState
+}
+
+func newWatcher(filename string) *watcher {
+	w := &watcher{
+		filename: filename,
+		interval: watchInterval,
+		debounce: watchDebounce,
+		sleep:    time.Sleep,
+	}
+	w.reset()
+	return w
+}
+
+// reset collects files again, since imports may be changed, and records their current states.
+func (w *watcher) reset() {
+	w.files = apiFiles(w.filename)
+	w.last = snapshot(w.files)
+}
+
+// wait blocks until watched files change and the changes settle, returns the changed files.
+func (w *watcher) wait() []string {
+	var cur map[string]fileState
+	for {
+		w.sleep(w.interval)
+		cur = snapshot(w.files)
+		if !equalStates(cur, w.last) {
+			break
+		}
+	}
+	for {
+		w.sleep(w.debounce)
+		next := snapshot(w.files)
+		if equalStates(next, cur) {
+			break
+		}
+		cur = next
+	}
+
+	var changed []string
+	for _, f := range w.files {
+		if cur[f] != w.last[f] {
+			changed = append(changed, f)
+		}
+	}
+	return changed
+}
+
+// watchFiles calls gen at once, then every time filename or files it imports change, it never returns.
+func watchFiles(filename string, gen func()) {
+	w := newWatcher(filename)
+	gen()
+	for {
+		for _, f := range w.wait() {
+			fmt.Printf("goctl-openapi: %s changed\n", f)
+		}
+		// files are collected again before generating, so that changes during generation trigger the next generation.
+		w.reset()
+		gen()
+	}
+}
+
+// apiFiles returns filename and files it imports recursively.
+// Files which can't be parsed are still returned, so that fixing them triggers generation.
+func apiFiles(filename string) []string {
+	var (
+		files []string
+		seen  = make(map[string]bool)
+		visit func(string)
+	)
+	visit = func(f string) {
+		if abs, err := filepath.Abs(f); err == nil {
+			f = abs
+		}
+		if seen[f] {
+			return
+		}
+		seen[f] = true
+		files = append(files, f)
+
+		// the parser exits the process if the file can't be read or is empty.
+		src, err := os.ReadFile(f)
+		if err != nil || len(src) == 0 {
+			return
+		}
+		p := parser.New(f, src)
+		a := p.Parse()
+		if a == nil || p.CheckErrors() != nil {
+			return
+		}
+		var imports []*ast.TokenNode
+		for _, stmt := range a.Stmts {
+			switch stmt := stmt.(type) {
+			case *ast.ImportLiteralStmt:
+				imports = append(imports, stmt.Value)
+			case *ast.ImportGroupStmt:
+				imports = append(imports, stmt.Values...)
+			}
+		}
+		for _, node := range imports {
+			imp, err := strconv.Unquote(node.Token.Text)
+			if err != nil || imp == "" {
+				continue
+			}
+			if !filepath.IsAbs(imp) {
+				imp = filepath.Join(filepath.Dir(f), imp)
+			}
+			visit(imp)
+		}
+	}
+	visit(filename)
+	return files
+}
+
+// snapshot returns states of files.
+func snapshot(files []string) map[string]fileState {
+	states := make(map[string]fileState, len(files))
+	for _, f := range files {
+		if info, err := os.Stat(f); err == nil {
+			states[f] = fileState{modTime: info.ModTime(), size: info.Size()}
+		} else {
+			states[f] = fileState{}
+		}
+	}
+	return states
+}
+
+// equalStates reports whether a and b have the same files in the same states.
+func equalStates(a, b map[string]fileState) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for f, s := range a {
+		if t, ok := b[f]; !ok || t != s {
+			return false
+		}
+	}
+	return true
+}
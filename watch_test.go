@@ -0,0 +1,170 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApiFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "types"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, dir, map[string]string{
+		"main.api": `syntax = "v1"
+
+import "types/book.api"
+import (
+	"types/author.api"
+	"missing.api"
+)
+`,
+		"types/book.api": `syntax = "v1"
+
+import "author.api"
+`,
+		"types/author.api": `syntax = "v1"
+
+import "../main.api"
+import "invalid.api"
+`,
+		"types/invalid.api": `syntax = "v1"
+
+import "never.api"
+type {
+`,
+	})
+
+	var got []string
+	for _, f := range apiFiles(filepath.Join(dir, "main.api")) {
+		rel, err := filepath.Rel(dir, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, filepath.ToSlash(rel))
+	}
+	want := "main.api,types/book.api,types/author.api,types/invalid.api,missing.api"
+	if strings.Join(got, ",") != want {
+		t.Errorf("apiFiles() = %s, want %s", strings.Join(got, ","), want)
+	}
+}
+
+func TestEqualStates(t *testing.T) {
+	now := time.Now()
+	states := map[string]fileState{
+		"a.api": {modTime: now, size: 1},
+		"b.api": {},
+	}
+	tests := []struct {
+		name  string
+		other map[string]fileState
+		want  bool
+	}{
+		{"same", map[string]fileState{"a.api": {modTime: now, size: 1}, "b.api": {}}, true},
+		{"added", map[string]fileState{"a.api": {modTime: now, size: 1}, "b.api": {}, "c.api": {}}, false},
+		{"removed", map[string]fileState{"a.api": {modTime: now, size: 1}}, false},
+		{"created", map[string]fileState{"a.api": {modTime: now, size: 1}, "b.api": {modTime: now}}, false},
+		{"modified", map[string]fileState{"a.api": {modTime: now.Add(time.Second), size: 1}, "b.api": {}}, false},
+		{"resized", map[string]fileState{"a.api": {modTime: now, size: 2}, "b.api": {}}, false},
+		{"renamed", map[string]fileState{"a.api": {modTime: now, size: 1}, "c.api": {}}, false},
+	}
+	for _, tt := range tests {
+		if got := equalStates(states, tt.other); got != tt.want {
+			t.Errorf("%s: equalStates() = %t, want %t", tt.name, got, tt.want)
+		}
+		if got := equalStates(tt.other, states); got != tt.want {
+			t.Errorf("%s: reversed equalStates() = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWatcherWait(t *testing.T) {
+	dir := t.TempDir()
+	mainFile, book := filepath.Join(dir, "main.api"), filepath.Join(dir, "book.api")
+	writeFiles(t, dir, map[string]string{
+		"main.api": "syntax = \"v1\"\n\nimport \"book.api\"\n",
+		"book.api": "syntax = \"v1\"\n",
+	})
+
+	// edits are made to files when the watcher sleeps for the nth time.
+	edits := map[int]func(){
+		3: func() { writeFiles(t, dir, map[string]string{"book.api": "syntax = \"v1\"\n\n"}) },
+		4: func() { writeFiles(t, dir, map[string]string{"book.api": "syntax = \"v1\"\n\n\n"}) },
+		7: func() { writeFiles(t, dir, map[string]string{"main.api": "syntax = \"v1\"\n"}) },
+	}
+	var slept []time.Duration
+	w := newWatcher(mainFile)
+	w.interval, w.debounce = time.Second, time.Millisecond
+	w.sleep = func(d time.Duration) {
+		slept = append(slept, d)
+		if edit := edits[len(slept)]; edit != nil {
+			edit()
+		}
+	}
+
+	// the change is detected by the 3rd poll, and settles after 2 debounces.
+	if got := w.wait(); strings.Join(got, ",") != book {
+		t.Errorf("wait() = %v, want %s", got, book)
+	}
+	want := []time.Duration{time.Second, time.Second, time.Second, time.Millisecond, time.Millisecond}
+	if len(slept) != len(want) {
+		t.Fatalf("slept %v, want %v", slept, want)
+	}
+	for i := range want {
+		if slept[i] != want[i] {
+			t.Fatalf("slept %v, want %v", slept, want)
+		}
+	}
+
+	// changes are reported until reset, main.api doesn't import book.api anymore.
+	if got := w.wait(); strings.Join(got, ",") != mainFile+","+book {
+		t.Errorf("wait() = %v, want %s and %s", got, mainFile, book)
+	}
+	w.reset()
+	if strings.Join(w.files, ",") != mainFile {
+		t.Errorf("files = %v, want %s", w.files, mainFile)
+	}
+}
+
+func TestRegenerateFailure(t *testing.T) {
+	dir := t.TempDir()
+	api := `syntax = "v1"
+
+type Book {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+service Books {
+	@handler GetBook
+	get /book returns (Book)
+}
+`
+	writeFiles(t, dir, map[string]string{"book.api": api})
+	args := []string{"-api", "book.api", "-embed", "openapi.go"}
+	if stdout, _ := command(t, dir, args...); stdout != "" {
+		t.Fatalf("generate: %s", stdout)
+	}
+	outputs := make(map[string][]byte)
+	for _, name := range []string{"openapi.json", "openapi.go"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs[name] = data
+	}
+
+	for _, broken := range []string{strings.Replace(api, "returns (Book)", "returns (Missing)", 1), "", "syntax = \"v1\"\ntype {"} {
+		writeFiles(t, dir, map[string]string{"book.api": broken})
+		if stdout, _ := command(t, dir, args...); !strings.HasPrefix(stdout, "goctl-openapi: ") {
+			t.Errorf("generate %q doesn't fail: %s", broken, stdout)
+		}
+		for name, want := range outputs {
+			if data, err := os.ReadFile(filepath.Join(dir, name)); err != nil || string(data) != string(want) {
+				t.Errorf("generate %q changes %s: %s, %v", broken, name, data, err)
+			}
+		}
+	}
+}